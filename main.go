@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	logic "github.com/dterbah/go-logic/src"
 	"github.com/sirupsen/logrus"
@@ -20,20 +21,182 @@ func setupLogger() {
 	logrus.SetLevel(logrus.InfoLevel)
 }
 
+/*
+Split a comma-separated list of variables, ignoring the spaces
+*/
+func splitVariables(list string) []string {
+	variables := []string{}
+	for _, variable := range strings.Split(list, ",") {
+		if variable = strings.TrimSpace(variable); variable != "" {
+			variables = append(variables, variable)
+		}
+	}
+	return variables
+}
+
+/*
+Flag that can be repeated, each occurrence adding a value
+*/
+type repeatedFlag []string
+
+func (values *repeatedFlag) String() string {
+	return strings.Join(*values, ", ")
+}
+
+func (values *repeatedFlag) Set(value string) error {
+	*values = append(*values, value)
+	return nil
+}
+
 func main() {
 	setupLogger()
 	logicExpression := flag.String("e", "", "Logic expression to evaluate")
 	generateGraph := flag.Bool("g", false, "Generate the grap representation of the expression")
 	generateTruthTable := flag.Bool("t", true, "Generate truth table")
+	tableFormatName := flag.String("format", "text", "Format of the truth table (text, csv, tsv, json, jsonl, markdown, latex, html)")
+	outputFile := flag.String("o", "", "File where the truth table is written instead of the standard output")
 	simplifyExpression := flag.Bool("s", false, "Simplify the expression")
+	minimizeExpression := flag.Bool("m", false, "Minimize the expression into a minimal sum of products (Quine-McCluskey)")
+	espressoEffortName := flag.String("espresso", "", "Minimize the expression with the Espresso heuristic, for many variables (fast, normal, exhaustive)")
+	dontCares := flag.String("dc", "", "Expression true on the rows that can not occur (don't cares), used by the minimizers and shown as X in the truth table")
+	dottedVariables := flag.Bool("dotted", false, "Allow dotted variable names such as user.isAdmin ('.' is no longer an AND operator between identifiers)")
+	dialectName := flag.String("dialect", "ascii", "Dialect used to print expressions (ascii, unicode, keywords, c)")
+	primes := flag.Bool("primes", false, "List the prime implicants, the essential ones being marked, the prime implicates and the Blake canonical form")
+	formsList := flag.String("forms", "", "Comma-separated normal forms printed after the truth table (dnf, cnf, nnf, anf, minterms, maxterms, all)")
+	kmapFormatName := flag.String("kmap", "", "Print the Karnaugh map of the expression, for 2 to 6 variables, with the groups of the minimized expression when -m or -espresso is set (terminal, markdown, html, svg)")
+	kmapFile := flag.String("kmapfile", "", "File where the Karnaugh map is written instead of the standard output")
+	dimacsFile := flag.String("dimacs", "", "Export the expression in the DIMACS CNF format to the given file")
+	encodingName := flag.String("encoding", "totalizer", "Encoding of the cardinality and pseudo-Boolean constraints in the DIMACS file (totalizer, counter, sorting)")
+	solve := flag.Bool("sat", false, "Search an assignment satisfying the expression with the SAT solver")
+	models := flag.Int("models", 0, "Print the models of the expression found by the SAT solver, at most this number (-1 for all of them)")
+	projection := flag.String("project", "", "Comma-separated variables the models are projected on")
+	countModels := flag.Bool("count", false, "Count the models of the expression without building the truth table")
+	probabilitiesList := flag.String("probability", "", "Print the probability that the expression is true, from the probabilities of its variables (a=0.3,b=0.9; 0.5 by default)")
+	graphKindName := flag.String("diagram", "tree", "Graph generated by -g: the parse tree of the expression or its binary decision diagram (tree, bdd)")
+	bddOrderName := flag.String("order", "dfs", "Order of the variables of the binary decision diagram (dfs, force, occurrence)")
+	complementEdges := flag.Bool("complement", false, "Draw the binary decision diagram with complement edges")
+	equivalentTo := flag.String("equiv", "", "Check that the expression is equivalent to this one, or print a row on which they differ")
+	checkName := flag.String("check", "", "Check that the expression is a tautology, a contradiction or satisfiable, the exit code being 1 when it is not (tautology, contradiction, satisfiable)")
+	var premises repeatedFlag
+	flag.Var(&premises, "premise", "Premise that must entail the expression, the exit code being 1 when they do not (can be repeated)")
+	var constraints repeatedFlag
+	flag.Var(&constraints, "constraint", "Constraint \"name: expression\" that must be consistent with the expression, a minimal contradictory subset being printed when it is not (can be repeated)")
+	var softConstraints repeatedFlag
+	flag.Var(&softConstraints, "soft", "Soft constraint \"name/weight: expression\", an assignment satisfying the expression and the constraints while minimizing the weight of the violated soft constraints being printed (can be repeated)")
 	flag.Parse()
 
 	if *logicExpression == "" {
 		fmt.Println("The -e option is required.")
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(logic.EXIT_ERROR)
 	}
 
-	runner := logic.NewRunner(*logicExpression, *generateGraph, *generateTruthTable, *simplifyExpression)
-	runner.Run()
+	dialect, err := logic.ParseDialect(*dialectName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(logic.EXIT_ERROR)
+	}
+
+	var espressoEffort logic.EspressoEffort
+	if *espressoEffortName != "" {
+		espressoEffort, err = logic.ParseEspressoEffort(*espressoEffortName)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(logic.EXIT_ERROR)
+		}
+	}
+
+	tableFormat, err := logic.ParseTruthTableFormat(*tableFormatName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(logic.EXIT_ERROR)
+	}
+
+	graphKind, err := logic.ParseGraphKind(*graphKindName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(logic.EXIT_ERROR)
+	}
+
+	bddOrder, err := logic.ParseBDDOrderHeuristic(*bddOrderName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(logic.EXIT_ERROR)
+	}
+
+	cardinalityEncoding, err := logic.ParseCardinalityEncoding(*encodingName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(logic.EXIT_ERROR)
+	}
+
+	var forms []logic.NormalForm
+	if *formsList != "" {
+		forms, err = logic.ParseNormalForms(*formsList)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(logic.EXIT_ERROR)
+		}
+	}
+
+	kmapFormat := logic.KMapNone
+	if *kmapFormatName != "" {
+		kmapFormat, err = logic.ParseKMapFormat(*kmapFormatName)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(logic.EXIT_ERROR)
+		}
+	}
+
+	check := logic.CheckNone
+	if *checkName != "" {
+		check, err = logic.ParseCheck(*checkName)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(logic.EXIT_ERROR)
+		}
+	}
+
+	var probabilities map[string]float64
+	if *probabilitiesList != "" {
+		probabilities, err = logic.ParseProbabilities(*probabilitiesList)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(logic.EXIT_ERROR)
+		}
+	}
+
+	runner := logic.NewRunnerWithOptions(*logicExpression, logic.RunnerOptions{
+		GenerateGraph:       *generateGraph,
+		GenerateTruthTable:  *generateTruthTable,
+		SimplifyExpression:  *simplifyExpression,
+		MinimizeExpression:  *minimizeExpression,
+		MinimizeHeuristic:   *espressoEffortName != "",
+		EspressoEffort:      espressoEffort,
+		DontCares:           *dontCares,
+		DottedVariables:     *dottedVariables,
+		Dialect:             dialect,
+		DIMACSFile:          *dimacsFile,
+		CardinalityEncoding: cardinalityEncoding,
+		Solve:               *solve,
+		Models:              *models,
+		Projection:          splitVariables(*projection),
+		CountModels:         *countModels,
+		Probabilities:       probabilities,
+		Graph:               graphKind,
+		BDDOrder:            bddOrder,
+		ComplementEdges:     *complementEdges,
+		EquivalentTo:        *equivalentTo,
+		Check:               check,
+		Premises:            premises,
+		Constraints:         constraints,
+		SoftConstraints:     softConstraints,
+		PrimeImplicants:     *primes,
+		Forms:               forms,
+		KMap:                kmapFormat,
+		KMapFile:            *kmapFile,
+		TruthTableFormat:    tableFormat,
+		OutputFile:          *outputFile,
+	})
+	os.Exit(runner.Run())
 }
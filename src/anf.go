@@ -0,0 +1,113 @@
+package logic
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Maximal number of variables of the algebraic normal form, computed on the truth table
+const MAX_ANF_VARIABLES = 20
+
+/*
+Algebraic normal form of a function, also called its Zhegalkin polynomial: the XOR of
+monomials, each one being the AND of a set of variables. The variable at index k of
+Variables belongs to a monomial when its bit k is set, the empty monomial being the
+constant 1. Every function has a single algebraic normal form
+*/
+type ANF struct {
+	Variables []string
+	Monomials []uint64 // Sorted by degree, then by the index of their variables
+}
+
+/*
+Compute the algebraic normal form of an expression with the Möbius transform of its
+truth table: the coefficient of a monomial is the XOR of the values of the function on
+the rows whose true variables belong to the monomial. An error is returned above
+MAX_ANF_VARIABLES variables
+*/
+func ToANF(expr Expression) (*ANF, error) {
+	variables := Variables(expr)
+	if len(variables) > MAX_ANF_VARIABLES {
+		return nil, fmt.Errorf("the algebraic normal form supports at most %d variables, found %d", MAX_ANF_VARIABLES, len(variables))
+	}
+
+	coefficients := make([]bool, 1<<len(variables))
+	for _, row := range mintermsOf(expr, variables) {
+		coefficients[row] = true
+	}
+
+	// after the step of a variable, the coefficient of a set containing it is the XOR of
+	// the values with and without the variable
+	for bit := 1; bit < len(coefficients); bit <<= 1 {
+		for row := range coefficients {
+			if row&bit != 0 {
+				coefficients[row] = coefficients[row] != coefficients[row^bit]
+			}
+		}
+	}
+
+	anf := &ANF{Variables: variables, Monomials: []uint64{}}
+	for monomial, coefficient := range coefficients {
+		if coefficient {
+			anf.Monomials = append(anf.Monomials, uint64(monomial))
+		}
+	}
+	sort.SliceStable(anf.Monomials, func(i, j int) bool {
+		return bits.OnesCount64(anf.Monomials[i]) < bits.OnesCount64(anf.Monomials[j])
+	})
+	return anf, nil
+}
+
+/*
+Return the degree of the polynomial, the largest number of variables of a monomial. The
+degree of the constant 0 is 0
+*/
+func (anf *ANF) Degree() int {
+	degree := 0
+	for _, monomial := range anf.Monomials {
+		degree = max(degree, bits.OnesCount64(monomial))
+	}
+	return degree
+}
+
+/*
+Return true if an odd number of monomials are true. Missing variables are false
+*/
+func (anf *ANF) Eval(variables map[string]bool) bool {
+	result := false
+	for _, monomial := range anf.Monomials {
+		result = result != anf.monomialExpression(monomial).Eval(variables)
+	}
+	return result
+}
+
+/*
+Return the polynomial as a XOR of conjunctions, 0 when it has no monomial
+*/
+func (anf *ANF) ToExpression() Expression {
+	var expr Expression
+	for _, monomial := range anf.Monomials {
+		if expr == nil {
+			expr = anf.monomialExpression(monomial)
+		} else {
+			expr = NewXORExpression(expr, anf.monomialExpression(monomial))
+		}
+	}
+
+	if expr == nil {
+		return NewNumberExpression(0)
+	}
+	return expr
+}
+
+/*
+Return the polynomial in the ASCII dialect, such as 1 + a + a & b
+*/
+func (anf *ANF) String() string {
+	return Print(anf.ToExpression(), ASCII)
+}
+
+func (anf *ANF) monomialExpression(monomial uint64) Expression {
+	return cube{value: monomial, mask: monomial}.toExpression(anf.Variables)
+}
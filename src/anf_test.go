@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToANF(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name           string
+		input          string
+		expected       string
+		expectedDegree int
+	}{
+		{"test variable", "a", "a", 1},
+		{"test not", "!a", "1 + a", 1},
+		{"test and", "a & b", "a & b", 2},
+		{"test or", "a | b", "a + b + a & b", 2},
+		{"test xor chain", "a + b + c + a", "b + c", 1},
+		{"test equivalence", "a <-> b", "1 + a + b", 1},
+		{"test implies", "a -> b", "1 + a + a & b", 2},
+		{"test majority", "a & b | a & c | b & c", "a & b + a & c + b & c", 2},
+		{"test contradiction", "a & !a", "0", 0},
+		{"test tautology", "a | !a", "1", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			anf, err := ToANF(expr)
+			assert.Nil(err)
+			assert.Equal(test.expected, anf.String(), test.name)
+			assert.Equal(test.expectedDegree, anf.Degree(), test.name)
+			assert.True(sameTruthTable(expr, anf.ToExpression()), test.name)
+		})
+	}
+}
+
+func TestToANFRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(38))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 5, variables)
+		anf, err := ToANF(expr)
+		assert.Nil(err)
+		for row := 0; row < 1<<len(anf.Variables); row++ {
+			assignment := assignmentOf(row, anf.Variables)
+			assert.Equal(expr.Eval(assignment), anf.Eval(assignment), Print(expr, ASCII))
+		}
+
+		// the polynomial is unique, so the ANF of the ANF has the same monomials
+		again, err := ToANF(anf.ToExpression())
+		assert.Nil(err)
+		assert.Equal(len(anf.Monomials), len(again.Monomials), Print(expr, ASCII))
+		assert.Equal(anf.Degree(), again.Degree(), Print(expr, ASCII))
+	}
+}
+
+func TestToANFTooManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a0 + a1 + a2 + a3 + a4 + a5 + a6 + a7 + a8 + a9 + a10 + a11 + a12 + a13 + a14 + a15 + a16 + a17 + a18 + a19 + a20")
+	_, err := ToANF(expr)
+	assert.Equal("the algebraic normal form supports at most 20 variables, found 21", err.Error())
+}
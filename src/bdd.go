@@ -0,0 +1,451 @@
+package logic
+
+import (
+	"math/big"
+)
+
+/*
+Node of a binary decision diagram, identified by its index in the manager
+*/
+type BDD int
+
+// Terminal nodes of the decision diagrams
+const (
+	BDDFalse BDD = 0
+	BDDTrue  BDD = 1
+)
+
+type bddNode struct {
+	variable  int // Index of the variable, -1 for the terminals
+	low, high BDD // Nodes when the variable is false, and true
+}
+
+type bddChildren struct {
+	low, high BDD
+}
+
+type iteKey struct {
+	f, g, h BDD
+}
+
+/*
+Manager of reduced ordered binary decision diagrams. Each node is unique: the unique
+table of each variable gives the node of a pair of children, so that two equivalent
+functions are the same node and can be compared in constant time. The results of the
+ITE operations are kept in a computed cache. The variables are ordered by level, the
+variable of a node having a lower level than the variables of its children
+*/
+type BDDManager struct {
+	nodes     []bddNode
+	unique    []map[bddChildren]BDD // Unique table of each variable
+	variables []string
+	indexes   map[string]int
+	levels    []int // Level of each variable
+	order     []int // Variable at each level
+	cache     map[iteKey]BDD
+	free      []BDD // Collected nodes, reused by the next nodes
+
+	refs             []int // Number of references of each node, only counted during a reordering
+	externalRefs     map[BDD]int
+	building         []BDD // Diagrams being combined by FromExpression, kept by the automatic reordering
+	autoReorder      bool
+	reorderThreshold int
+	statistics       BDDStatistics
+}
+
+/*
+Create a manager whose variables are ordered as given
+*/
+func NewBDDManager(variables ...string) *BDDManager {
+	manager := &BDDManager{
+		nodes:        []bddNode{{variable: -1}, {variable: -1}},
+		indexes:      make(map[string]int),
+		cache:        make(map[iteKey]BDD),
+		externalRefs: make(map[BDD]int),
+	}
+	for _, variable := range variables {
+		manager.variableIndex(variable)
+	}
+	return manager
+}
+
+/*
+Return the index of a variable, adding it below the other variables if it does not exist yet
+*/
+func (manager *BDDManager) variableIndex(name string) int {
+	if index, ok := manager.indexes[name]; ok {
+		return index
+	}
+
+	index := len(manager.variables)
+	manager.variables = append(manager.variables, name)
+	manager.indexes[name] = index
+	manager.unique = append(manager.unique, make(map[bddChildren]BDD))
+	manager.levels = append(manager.levels, len(manager.order))
+	manager.order = append(manager.order, index)
+	return index
+}
+
+/*
+Return the variables, from the top level to the bottom one
+*/
+func (manager *BDDManager) Order() []string {
+	order := make([]string, len(manager.order))
+	for level, variable := range manager.order {
+		order[level] = manager.variables[variable]
+	}
+	return order
+}
+
+/*
+Return the diagram of a variable
+*/
+func (manager *BDDManager) Variable(name string) BDD {
+	return manager.makeNode(manager.variableIndex(name), BDDFalse, BDDTrue)
+}
+
+/*
+Return the diagram of a constant
+*/
+func (manager *BDDManager) Constant(value bool) BDD {
+	if value {
+		return BDDTrue
+	}
+	return BDDFalse
+}
+
+/*
+Return the node of a variable with the given children, creating it if needed
+*/
+func (manager *BDDManager) makeNode(variable int, low, high BDD) BDD {
+	if low == high {
+		return low
+	}
+
+	children := bddChildren{low: low, high: high}
+	if node, ok := manager.unique[variable][children]; ok {
+		return node
+	}
+
+	node := BDD(len(manager.nodes))
+	if len(manager.free) > 0 {
+		node = manager.free[len(manager.free)-1]
+		manager.free = manager.free[:len(manager.free)-1]
+		manager.nodes[node] = bddNode{variable: variable, low: low, high: high}
+	} else {
+		manager.nodes = append(manager.nodes, bddNode{variable: variable, low: low, high: high})
+	}
+	manager.unique[variable][children] = node
+	manager.statistics.PeakNodes = max(manager.statistics.PeakNodes, len(manager.nodes)-len(manager.free))
+
+	// during a reordering, the new node references its children
+	if manager.refs != nil {
+		if int(node) == len(manager.refs) {
+			manager.refs = append(manager.refs, 0)
+		}
+		manager.reference(low)
+		manager.reference(high)
+	}
+	return node
+}
+
+/*
+Return the level of a node, the terminals being below every variable
+*/
+func (manager *BDDManager) level(node BDD) int {
+	if node <= BDDTrue {
+		return len(manager.order)
+	}
+	return manager.levels[manager.nodes[node].variable]
+}
+
+/*
+Return the children of a node for the variable at the given level
+*/
+func (manager *BDDManager) cofactors(node BDD, level int) (BDD, BDD) {
+	if manager.level(node) != level {
+		return node, node
+	}
+	return manager.nodes[node].low, manager.nodes[node].high
+}
+
+/*
+Return the diagram of "if f then g else h", from which all the operators are built
+*/
+func (manager *BDDManager) ITE(f, g, h BDD) BDD {
+	switch {
+	case f == BDDTrue:
+		return g
+	case f == BDDFalse:
+		return h
+	case g == h:
+		return g
+	case g == BDDTrue && h == BDDFalse:
+		return f
+	}
+
+	key := iteKey{f, g, h}
+	if result, ok := manager.cache[key]; ok {
+		return result
+	}
+
+	level := min(manager.level(f), manager.level(g), manager.level(h))
+	fLow, fHigh := manager.cofactors(f, level)
+	gLow, gHigh := manager.cofactors(g, level)
+	hLow, hHigh := manager.cofactors(h, level)
+
+	low := manager.ITE(fLow, gLow, hLow)
+	high := manager.ITE(fHigh, gHigh, hHigh)
+	result := manager.makeNode(manager.order[level], low, high)
+
+	manager.cache[key] = result
+	return result
+}
+
+func (manager *BDDManager) Not(f BDD) BDD {
+	return manager.ITE(f, BDDFalse, BDDTrue)
+}
+
+func (manager *BDDManager) And(f, g BDD) BDD {
+	return manager.ITE(f, g, BDDFalse)
+}
+
+func (manager *BDDManager) Or(f, g BDD) BDD {
+	return manager.ITE(f, BDDTrue, g)
+}
+
+func (manager *BDDManager) XOR(f, g BDD) BDD {
+	return manager.ITE(f, manager.Not(g), g)
+}
+
+func (manager *BDDManager) Implies(f, g BDD) BDD {
+	return manager.ITE(f, g, BDDTrue)
+}
+
+func (manager *BDDManager) Equivalence(f, g BDD) BDD {
+	return manager.ITE(f, g, manager.Not(g))
+}
+
+/*
+Build the diagram of an expression. Its variables missing from the manager are added
+below the others, in their order of appearance
+*/
+func (manager *BDDManager) FromExpression(expr Expression) BDD {
+	var result BDD
+	switch value := expr.(type) {
+	case *VarExpression:
+		return manager.Variable(value.variable)
+	case *NumberExpression:
+		return manager.Constant(value.value == 1)
+	case *NotExpression:
+		result = manager.Not(manager.FromExpression(value.expr))
+	case *CardinalityExpression, *PseudoBooleanExpression:
+		result = manager.fromLinearConstraint(expr)
+	default:
+		children := operands(expr)
+		left := manager.FromExpression(children[0])
+		manager.building = append(manager.building, left)
+		right := manager.FromExpression(children[1])
+		manager.building = manager.building[:len(manager.building)-1]
+
+		switch expr.(type) {
+		case *AndExpression:
+			result = manager.And(left, right)
+		case *OrExpression:
+			result = manager.Or(left, right)
+		case *XORExpression:
+			result = manager.XOR(left, right)
+		case *ImpliesExpression:
+			result = manager.Implies(left, right)
+		default:
+			result = manager.Equivalence(left, right)
+		}
+	}
+
+	manager.checkReorder(result)
+	return result
+}
+
+/*
+Build the diagram of a cardinality or pseudo-Boolean constraint from the diagrams of its
+operands, the diagram "the operands from i weigh at least k" being
+ITE(operand i, at least k - weight i from i+1, at least k from i+1)
+*/
+func (manager *BDDManager) fromLinearConstraint(expr Expression) BDD {
+	constraint, _ := linearConstraintOf(expr)
+	start := len(manager.building)
+	operands := []BDD{}
+	for _, operand := range constraint.operands {
+		operands = append(operands, manager.FromExpression(operand))
+		manager.building = append(manager.building, operands[len(operands)-1])
+	}
+	manager.building = manager.building[:start]
+
+	// suffixes[i] is the sum of the weights from the operand i
+	suffixes := make([]int, len(operands)+1)
+	for index := len(operands) - 1; index >= 0; index-- {
+		suffixes[index] = suffixes[index+1] + constraint.weights[index]
+	}
+
+	type key struct{ index, bound int }
+	cache := make(map[key]BDD)
+	var atLeast func(index, bound int) BDD
+	atLeast = func(index, bound int) BDD {
+		if bound <= 0 {
+			return BDDTrue
+		}
+		if suffixes[index] < bound {
+			return BDDFalse
+		}
+		if result, ok := cache[key{index, bound}]; ok {
+			return result
+		}
+
+		result := manager.ITE(operands[index], atLeast(index+1, bound-constraint.weights[index]), atLeast(index+1, bound))
+		cache[key{index, bound}] = result
+		return result
+	}
+
+	result := BDDTrue
+	if constraint.lower > 0 {
+		result = atLeast(0, constraint.lower)
+	}
+	if constraint.upper < suffixes[0] {
+		result = manager.And(result, manager.Not(atLeast(0, constraint.upper+1)))
+	}
+	return result
+}
+
+/*
+Convert a diagram into an expression, by expanding each node on its variable:
+x & high | !x & low
+*/
+func (manager *BDDManager) ToExpression(f BDD) Expression {
+	expressions := make(map[BDD]Expression)
+	return manager.toExpression(f, expressions)
+}
+
+func (manager *BDDManager) toExpression(f BDD, expressions map[BDD]Expression) Expression {
+	if f <= BDDTrue {
+		return NewNumberExpression(int(f))
+	}
+	if expr, ok := expressions[f]; ok {
+		return expr
+	}
+
+	node := manager.nodes[f]
+	variable := NewVarExpression(manager.variables[node.variable])
+	var expr Expression
+	switch {
+	case node.low == BDDFalse && node.high == BDDTrue:
+		expr = variable
+	case node.low == BDDTrue && node.high == BDDFalse:
+		expr = NewNotExpression(variable)
+	case node.low == BDDFalse:
+		expr = NewAndExpression(variable, manager.toExpression(node.high, expressions))
+	case node.high == BDDFalse:
+		expr = NewAndExpression(NewNotExpression(variable), manager.toExpression(node.low, expressions))
+	case node.high == BDDTrue:
+		expr = NewOrExpression(variable, manager.toExpression(node.low, expressions))
+	case node.low == BDDTrue:
+		expr = NewOrExpression(NewNotExpression(variable), manager.toExpression(node.high, expressions))
+	default:
+		expr = NewOrExpression(
+			NewAndExpression(variable, manager.toExpression(node.high, expressions)),
+			NewAndExpression(NewNotExpression(variable), manager.toExpression(node.low, expressions)),
+		)
+	}
+
+	expressions[f] = expr
+	return expr
+}
+
+/*
+Evaluate a diagram by following the path of the assignment. Missing variables are false
+*/
+func (manager *BDDManager) Eval(f BDD, variables map[string]bool) bool {
+	for f > BDDTrue {
+		node := manager.nodes[f]
+		if variables[manager.variables[node.variable]] {
+			f = node.high
+		} else {
+			f = node.low
+		}
+	}
+	return f == BDDTrue
+}
+
+/*
+Return the number of nodes of a diagram, terminals included
+*/
+func (manager *BDDManager) NodeCount(roots ...BDD) int {
+	visited := make(map[BDD]bool)
+	var visit func(node BDD)
+	visit = func(node BDD) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		if node > BDDTrue {
+			visit(manager.nodes[node].low)
+			visit(manager.nodes[node].high)
+		}
+	}
+
+	for _, root := range roots {
+		visit(root)
+	}
+	return len(visited)
+}
+
+/*
+Count the assignments of all the variables of the manager for which the diagram is
+true, in a time linear in the number of nodes
+*/
+func (manager *BDDManager) SatCount(f BDD) *big.Int {
+	counts := make(map[BDD]*big.Int)
+
+	// number of assignments of the variables from the level of the node to the bottom
+	var count func(node BDD) *big.Int
+	count = func(node BDD) *big.Int {
+		if node <= BDDTrue {
+			return big.NewInt(int64(node))
+		}
+		if result, ok := counts[node]; ok {
+			return result
+		}
+
+		level := manager.level(node)
+		low, high := manager.nodes[node].low, manager.nodes[node].high
+		lowCount := new(big.Int).Lsh(count(low), uint(manager.level(low)-level-1))
+		highCount := new(big.Int).Lsh(count(high), uint(manager.level(high)-level-1))
+		result := lowCount.Add(lowCount, highCount)
+		counts[node] = result
+		return result
+	}
+
+	return new(big.Int).Lsh(count(f), uint(manager.level(f)))
+}
+
+/*
+Return an assignment of the variables on the path of the diagram to the true terminal,
+or false when the diagram is the false terminal
+*/
+func (manager *BDDManager) AnySat(f BDD) (map[string]bool, bool) {
+	if f == BDDFalse {
+		return nil, false
+	}
+
+	assignment := make(map[string]bool)
+	for f > BDDTrue {
+		node := manager.nodes[f]
+		value := node.low == BDDFalse
+		assignment[manager.variables[node.variable]] = value
+		if value {
+			f = node.high
+		} else {
+			f = node.low
+		}
+	}
+	return assignment, true
+}
@@ -0,0 +1,505 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A variable stops moving in a direction when the diagrams grow above this factor of the best size
+const SIFT_MAX_GROWTH = 1.2
+
+// Number of nodes above which the automatic reordering is triggered for the first time
+const BDD_REORDER_THRESHOLD = 4000
+
+// Maximal number of iterations of the FORCE heuristic
+const FORCE_MAX_ITERATIONS = 100
+
+/*
+Static heuristic choosing the order of the variables of a decision diagram
+*/
+type BDDOrderHeuristic int
+
+const (
+	BDDOrderDFS        BDDOrderHeuristic = iota // Order of appearance in the expression
+	BDDOrderFORCE                               // Variables sharing operators are placed close to each other
+	BDDOrderOccurrence                          // Most frequent variables first
+)
+
+var bddOrderHeuristicNames = map[BDDOrderHeuristic]string{
+	BDDOrderDFS:        "dfs",
+	BDDOrderFORCE:      "force",
+	BDDOrderOccurrence: "occurrence",
+}
+
+func (heuristic BDDOrderHeuristic) String() string {
+	return bddOrderHeuristicNames[heuristic]
+}
+
+/*
+Return the heuristic of a name (dfs, force, occurrence)
+*/
+func ParseBDDOrderHeuristic(name string) (BDDOrderHeuristic, error) {
+	for heuristic, heuristicName := range bddOrderHeuristicNames {
+		if heuristicName == strings.ToLower(name) {
+			return heuristic, nil
+		}
+	}
+	return BDDOrderDFS, fmt.Errorf("unknown variable order '%s', expected dfs, force or occurrence", name)
+}
+
+/*
+Return the variables of the expression in the order chosen by the heuristic
+*/
+func VariableOrder(expr Expression, heuristic BDDOrderHeuristic) []string {
+	switch heuristic {
+	case BDDOrderFORCE:
+		return FORCEOrder(expr)
+	case BDDOrderOccurrence:
+		return OccurrenceOrder(expr)
+	default:
+		return DFSOrder(expr)
+	}
+}
+
+/*
+Return the variables in the order of a depth first traversal of the expression, which
+keeps close the variables of the same sub-expression
+*/
+func DFSOrder(expr Expression) []string {
+	return Variables(expr)
+}
+
+/*
+Return the variables from the most frequent to the least frequent, the ties being
+kept in their order of appearance
+*/
+func OccurrenceOrder(expr Expression) []string {
+	occurrences := make(map[string]int)
+	var count func(expr Expression)
+	count = func(expr Expression) {
+		if value, ok := expr.(*VarExpression); ok {
+			occurrences[value.variable]++
+		}
+		for _, operand := range operands(expr) {
+			count(operand)
+		}
+	}
+	count(expr)
+
+	order := Variables(expr)
+	sort.SliceStable(order, func(i, j int) bool {
+		return occurrences[order[i]] > occurrences[order[j]]
+	})
+	return order
+}
+
+/*
+Return the variables ordered by the FORCE heuristic. Each clause of the Tseitin encoding
+of the expression is an edge linking its variables. Each variable is moved to the mean of
+the centers of its edges, until the total span of the edges stops decreasing
+*/
+func FORCEOrder(expr Expression) []string {
+	cnf := TseitinCNF(expr)
+	nbrVariables := len(cnf.Variables)
+
+	positions := make([]float64, nbrVariables+1)
+	for variable := 1; variable <= nbrVariables; variable++ {
+		positions[variable] = float64(variable)
+	}
+
+	edgesOf := make([][]int, nbrVariables+1)
+	for index, clause := range cnf.Clauses {
+		for _, literal := range clause {
+			edgesOf[literal.Variable()] = append(edgesOf[literal.Variable()], index)
+		}
+	}
+
+	order := make([]int, nbrVariables)
+	for index := range order {
+		order[index] = index + 1
+	}
+
+	bestSpan := forceSpan(cnf.Clauses, positions)
+	bestOrder := append([]int{}, order...)
+	for iteration := 0; iteration < FORCE_MAX_ITERATIONS; iteration++ {
+		centers := make([]float64, len(cnf.Clauses))
+		for index, clause := range cnf.Clauses {
+			for _, literal := range clause {
+				centers[index] += positions[literal.Variable()]
+			}
+			centers[index] /= float64(len(clause))
+		}
+
+		target := make([]float64, nbrVariables+1)
+		for variable := 1; variable <= nbrVariables; variable++ {
+			target[variable] = positions[variable]
+			if len(edgesOf[variable]) > 0 {
+				target[variable] = 0
+				for _, edge := range edgesOf[variable] {
+					target[variable] += centers[edge]
+				}
+				target[variable] /= float64(len(edgesOf[variable]))
+			}
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return target[order[i]] < target[order[j]]
+		})
+		for position, variable := range order {
+			positions[variable] = float64(position + 1)
+		}
+
+		span := forceSpan(cnf.Clauses, positions)
+		if span >= bestSpan {
+			break
+		}
+		bestSpan = span
+		bestOrder = append(bestOrder[:0], order...)
+	}
+
+	// the auxiliary variables are not part of the order
+	variables := []string{}
+	for _, variable := range bestOrder {
+		if variable <= cnf.NbrInputs {
+			variables = append(variables, cnf.Variables[variable-1])
+		}
+	}
+	return variables
+}
+
+/*
+Return the sum of the distances between the first and the last variable of each clause
+*/
+func forceSpan(clauses []Clause, positions []float64) float64 {
+	span := 0.0
+	for _, clause := range clauses {
+		if len(clause) == 0 {
+			continue
+		}
+
+		first, last := positions[clause[0].Variable()], positions[clause[0].Variable()]
+		for _, literal := range clause {
+			first = min(first, positions[literal.Variable()])
+			last = max(last, positions[literal.Variable()])
+		}
+		span += last - first
+	}
+	return span
+}
+
+/*
+Build the diagram of an expression in a new manager, with the variables ordered by the heuristic
+*/
+func NewBDD(expr Expression, heuristic BDDOrderHeuristic) (*BDDManager, BDD) {
+	manager := NewBDDManager(VariableOrder(expr, heuristic)...)
+	return manager, manager.FromExpression(expr)
+}
+
+/*
+Statistics of a reordering of the variables
+*/
+type BDDReorderStatistics struct {
+	NodesBefore int // Number of nodes of the diagrams before the reordering, terminals included
+	NodesAfter  int
+	Swaps       int // Number of swaps of adjacent levels
+	Duration    time.Duration
+}
+
+/*
+Statistics of a manager
+*/
+type BDDStatistics struct {
+	Nodes       int // Number of allocated nodes, terminals included
+	PeakNodes   int
+	Reorderings int
+	LastReorder BDDReorderStatistics
+}
+
+/*
+Return the statistics of the manager
+*/
+func (manager *BDDManager) Statistics() BDDStatistics {
+	statistics := manager.statistics
+	statistics.Nodes = len(manager.nodes) - len(manager.free)
+	return statistics
+}
+
+/*
+Keep a diagram when the nodes are collected during a reordering
+*/
+func (manager *BDDManager) Ref(f BDD) {
+	manager.externalRefs[f]++
+}
+
+/*
+Release a diagram kept by Ref
+*/
+func (manager *BDDManager) Deref(f BDD) {
+	if manager.externalRefs[f] <= 1 {
+		delete(manager.externalRefs, f)
+	} else {
+		manager.externalRefs[f]--
+	}
+}
+
+/*
+Reorder the variables automatically while building diagrams from expressions, when the
+number of nodes doubles. Only the diagrams kept by Ref are preserved by a reordering:
+the other nodes built before can be collected
+*/
+func (manager *BDDManager) EnableAutoReorder() {
+	manager.autoReorder = true
+	manager.reorderThreshold = max(BDD_REORDER_THRESHOLD, 2*(len(manager.nodes)-len(manager.free)))
+}
+
+func (manager *BDDManager) DisableAutoReorder() {
+	manager.autoReorder = false
+}
+
+/*
+Reorder the variables with the Rudell's sifting: each variable, from the level with the
+most nodes, is moved to every level by swapping adjacent levels, then put back at the
+level giving the smallest diagrams. The nodes keep their identifiers, so the roots
+stay valid, and the nodes not reachable from the roots or a Ref are collected
+*/
+func (manager *BDDManager) Sift(roots ...BDD) BDDReorderStatistics {
+	start := time.Now()
+	manager.startReordering(roots)
+	statistics := BDDReorderStatistics{NodesBefore: manager.liveNodes()}
+
+	variables := append([]int{}, manager.order...)
+	sort.SliceStable(variables, func(i, j int) bool {
+		return len(manager.unique[variables[i]]) > len(manager.unique[variables[j]])
+	})
+
+	for _, variable := range variables {
+		statistics.Swaps += manager.siftVariable(variable)
+	}
+
+	statistics.NodesAfter = manager.liveNodes()
+	statistics.Duration = time.Since(start)
+	manager.stopReordering()
+
+	manager.statistics.Reorderings++
+	manager.statistics.LastReorder = statistics
+	return statistics
+}
+
+/*
+Reorder the variables in the given order, the missing variables keeping their relative
+order below the given ones. The roots stay valid, as with Sift
+*/
+func (manager *BDDManager) SetOrder(order []string, roots ...BDD) {
+	manager.startReordering(roots)
+	for target, name := range order {
+		variable := manager.variableIndex(name)
+		for manager.levels[variable] > target {
+			manager.swapLevels(manager.levels[variable] - 1)
+		}
+	}
+	manager.stopReordering()
+}
+
+/*
+Move a variable to the level giving the fewest nodes, and return the number of swaps
+*/
+func (manager *BDDManager) siftVariable(variable int) int {
+	best, bestLevel := manager.liveNodes(), manager.levels[variable]
+	swaps := 0
+
+	up := func() {
+		for manager.levels[variable] > 0 {
+			manager.swapLevels(manager.levels[variable] - 1)
+			swaps++
+			if size := manager.liveNodes(); size < best {
+				best, bestLevel = size, manager.levels[variable]
+			} else if float64(size) > SIFT_MAX_GROWTH*float64(best) {
+				return
+			}
+		}
+	}
+
+	down := func() {
+		for manager.levels[variable] < len(manager.order)-1 {
+			manager.swapLevels(manager.levels[variable])
+			swaps++
+			if size := manager.liveNodes(); size < best {
+				best, bestLevel = size, manager.levels[variable]
+			} else if float64(size) > SIFT_MAX_GROWTH*float64(best) {
+				return
+			}
+		}
+	}
+
+	// the closest end first
+	if manager.levels[variable] < len(manager.order)/2 {
+		up()
+		down()
+	} else {
+		down()
+		up()
+	}
+
+	for manager.levels[variable] > bestLevel {
+		manager.swapLevels(manager.levels[variable] - 1)
+		swaps++
+	}
+	for manager.levels[variable] < bestLevel {
+		manager.swapLevels(manager.levels[variable])
+		swaps++
+	}
+	return swaps
+}
+
+/*
+Swap the variables of a level and of the level below in place: each node of the upper
+variable depending on the lower variable becomes a node of the lower variable whose
+children are nodes of the upper variable, and keeps its identifier
+*/
+func (manager *BDDManager) swapLevels(level int) {
+	x, y := manager.order[level], manager.order[level+1]
+
+	nodes := make([]BDD, 0, len(manager.unique[x]))
+	for _, node := range manager.unique[x] {
+		nodes = append(nodes, node)
+	}
+
+	for _, f := range nodes {
+		f0, f1 := manager.nodes[f].low, manager.nodes[f].high
+		if !manager.hasVariable(f0, y) && !manager.hasVariable(f1, y) {
+			continue
+		}
+
+		f00, f01 := manager.childrenOf(f0, y)
+		f10, f11 := manager.childrenOf(f1, y)
+		low := manager.makeNode(x, f00, f10)
+		manager.reference(low)
+		high := manager.makeNode(x, f01, f11)
+		manager.reference(high)
+
+		delete(manager.unique[x], bddChildren{low: f0, high: f1})
+		manager.nodes[f] = bddNode{variable: y, low: low, high: high}
+		manager.unique[y][bddChildren{low: low, high: high}] = f
+
+		manager.dereference(f0)
+		manager.dereference(f1)
+	}
+
+	manager.order[level], manager.order[level+1] = y, x
+	manager.levels[x], manager.levels[y] = level+1, level
+}
+
+func (manager *BDDManager) hasVariable(node BDD, variable int) bool {
+	return node > BDDTrue && manager.nodes[node].variable == variable
+}
+
+func (manager *BDDManager) childrenOf(node BDD, variable int) (BDD, BDD) {
+	if manager.hasVariable(node, variable) {
+		return manager.nodes[node].low, manager.nodes[node].high
+	}
+	return node, node
+}
+
+/*
+Count the references of the nodes reachable from the roots, and collect the other nodes
+*/
+func (manager *BDDManager) startReordering(roots []BDD) {
+	manager.refs = make([]int, len(manager.nodes))
+	reachable := make([]bool, len(manager.nodes))
+
+	var mark func(node BDD)
+	mark = func(node BDD) {
+		if reachable[node] {
+			return
+		}
+		reachable[node] = true
+		if node > BDDTrue {
+			mark(manager.nodes[node].low)
+			mark(manager.nodes[node].high)
+		}
+	}
+
+	for _, root := range roots {
+		manager.refs[root]++
+		mark(root)
+	}
+	for root, count := range manager.externalRefs {
+		manager.refs[root] += count
+		mark(root)
+	}
+
+	for node := BDD(2); int(node) < len(manager.nodes); node++ {
+		if reachable[node] {
+			manager.refs[manager.nodes[node].low]++
+			manager.refs[manager.nodes[node].high]++
+		} else if !manager.isFree(node) {
+			manager.freeNode(node)
+		}
+	}
+}
+
+func (manager *BDDManager) stopReordering() {
+	manager.refs = nil
+	manager.cache = make(map[iteKey]BDD)
+}
+
+func (manager *BDDManager) reference(node BDD) {
+	if node > BDDTrue {
+		manager.refs[node]++
+	}
+}
+
+/*
+Remove a reference to a node, collecting it when it is no longer used
+*/
+func (manager *BDDManager) dereference(node BDD) {
+	if node <= BDDTrue {
+		return
+	}
+
+	manager.refs[node]--
+	if manager.refs[node] == 0 {
+		low, high := manager.nodes[node].low, manager.nodes[node].high
+		manager.freeNode(node)
+		manager.dereference(low)
+		manager.dereference(high)
+	}
+}
+
+func (manager *BDDManager) freeNode(node BDD) {
+	current := manager.nodes[node]
+	delete(manager.unique[current.variable], bddChildren{low: current.low, high: current.high})
+	manager.nodes[node] = bddNode{variable: -1}
+	manager.free = append(manager.free, node)
+}
+
+func (manager *BDDManager) isFree(node BDD) bool {
+	return node > BDDTrue && manager.nodes[node].variable < 0
+}
+
+/*
+Return the number of nodes in the unique tables, terminals included
+*/
+func (manager *BDDManager) liveNodes() int {
+	nodes := 2
+	for _, unique := range manager.unique {
+		nodes += len(unique)
+	}
+	return nodes
+}
+
+/*
+Reorder the variables if the automatic reordering is enabled and the number of nodes
+went above the threshold, keeping the diagrams being built
+*/
+func (manager *BDDManager) checkReorder(result BDD) {
+	if !manager.autoReorder || len(manager.nodes)-len(manager.free) <= manager.reorderThreshold {
+		return
+	}
+
+	roots := append(append([]BDD{}, manager.building...), result)
+	statistics := manager.Sift(roots...)
+	manager.reorderThreshold = max(manager.reorderThreshold, 2*statistics.NodesAfter)
+}
@@ -0,0 +1,182 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return x0 & y0 | x1 & y1 | ..., whose diagram is linear when each x is next to its y,
+and exponential when all the x come first
+*/
+func pairsExpression(nbrPairs int) Expression {
+	var expr Expression
+	for index := 0; index < nbrPairs; index++ {
+		pair := NewAndExpression(NewVarExpression(fmt.Sprintf("x%d", index)), NewVarExpression(fmt.Sprintf("y%d", index)))
+		if expr == nil {
+			expr = pair
+		} else {
+			expr = NewOrExpression(expr, pair)
+		}
+	}
+	return expr
+}
+
+/*
+Return the variables x0, x1, ..., then y0, y1, ...
+*/
+func separatedOrder(nbrPairs int) []string {
+	order := []string{}
+	for _, prefix := range []string{"x", "y"} {
+		for index := 0; index < nbrPairs; index++ {
+			order = append(order, fmt.Sprintf("%s%d", prefix, index))
+		}
+	}
+	return order
+}
+
+func TestVariableOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a & (b | c) & (c + d) & (c -> a)")
+	assert.Equal([]string{"a", "b", "c", "d"}, VariableOrder(expr, BDDOrderDFS))
+	assert.Equal([]string{"c", "a", "b", "d"}, VariableOrder(expr, BDDOrderOccurrence))
+	assert.ElementsMatch([]string{"a", "b", "c", "d"}, VariableOrder(expr, BDDOrderFORCE))
+}
+
+func TestFORCEOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	// the x variables all appear before the y variables
+	var expr Expression = NewNumberExpression(0)
+	for index := 0; index < 8; index++ {
+		expr = NewXORExpression(expr, NewVarExpression(fmt.Sprintf("x%d", index)))
+	}
+	for index := 0; index < 8; index++ {
+		equivalence := NewEquivalenceExpression(NewVarExpression(fmt.Sprintf("x%d", index)), NewVarExpression(fmt.Sprintf("y%d", index)))
+		expr = NewAndExpression(expr, equivalence)
+	}
+
+	dfsManager, dfs := NewBDD(expr, BDDOrderDFS)
+	forceManager, force := NewBDD(expr, BDDOrderFORCE)
+	assert.Less(forceManager.NodeCount(force), dfsManager.NodeCount(dfs))
+	assert.True(sameTruthTable(expr, forceManager.ToExpression(force)))
+}
+
+func TestParseBDDOrderHeuristic(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, heuristic := range []BDDOrderHeuristic{BDDOrderDFS, BDDOrderFORCE, BDDOrderOccurrence} {
+		parsed, err := ParseBDDOrderHeuristic(heuristic.String())
+		assert.Nil(err)
+		assert.Equal(heuristic, parsed)
+	}
+
+	_, err := ParseBDDOrderHeuristic("random")
+	assert.Equal("unknown variable order 'random', expected dfs, force or occurrence", err.Error())
+}
+
+func TestSift(t *testing.T) {
+	assert := assert.New(t)
+
+	expr := pairsExpression(8)
+	manager := NewBDDManager(separatedOrder(8)...)
+	f := manager.FromExpression(expr)
+	before := manager.NodeCount(f)
+
+	statistics := manager.Sift(f)
+	assert.Equal(before, statistics.NodesBefore)
+	assert.Equal(manager.NodeCount(f), statistics.NodesAfter)
+	assert.Greater(statistics.Swaps, 0)
+
+	// the diagram is linear once each x is next to its y
+	assert.Equal(2*8+2, statistics.NodesAfter)
+	assert.True(sameTruthTable(expr, manager.ToExpression(f)))
+
+	// the nodes are still canonical
+	assert.Equal(f, manager.FromExpression(expr))
+	assert.Equal(1, manager.Statistics().Reorderings)
+}
+
+func TestSiftRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(22))
+	variables := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i := 0; i < 100; i++ {
+		first := randomExpression(random, 5, variables)
+		second := randomExpression(random, 5, variables)
+		manager := NewBDDManager(variables...)
+		f, g := manager.FromExpression(first), manager.FromExpression(second)
+
+		statistics := manager.Sift(f, g)
+		assert.LessOrEqual(statistics.NodesAfter, statistics.NodesBefore)
+		assert.Equal(manager.Statistics().Nodes, statistics.NodesAfter)
+
+		for row := 0; row < 1<<len(variables); row++ {
+			assignment := assignmentOf(row, variables)
+			assert.Equal(first.Eval(assignment), manager.Eval(f, assignment))
+			assert.Equal(second.Eval(assignment), manager.Eval(g, assignment))
+		}
+		assert.Equal(f, manager.FromExpression(first))
+		assert.Equal(g, manager.FromExpression(second))
+	}
+}
+
+func TestSetOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	expr := pairsExpression(4)
+	manager := NewBDDManager()
+	f := manager.FromExpression(expr)
+	assert.Equal(2*4+2, manager.NodeCount(f))
+
+	manager.SetOrder(separatedOrder(4), f)
+	assert.Equal(separatedOrder(4), manager.Order())
+	assert.Greater(manager.NodeCount(f), 2*4+2)
+	assert.True(sameTruthTable(expr, manager.ToExpression(f)))
+}
+
+func TestAutoReorder(t *testing.T) {
+	assert := assert.New(t)
+
+	// without reordering, the diagram has about 2^16 nodes
+	expr := pairsExpression(16)
+	manager := NewBDDManager(separatedOrder(16)...)
+	manager.EnableAutoReorder()
+	f := manager.FromExpression(expr)
+
+	assert.Greater(manager.Statistics().Reorderings, 0)
+	assert.Less(manager.NodeCount(f), 1000)
+	assert.Less(manager.Statistics().PeakNodes, 1<<16)
+
+	random := rand.New(rand.NewSource(23))
+	for i := 0; i < 200; i++ {
+		assignment := make(map[string]bool)
+		for _, variable := range separatedOrder(16) {
+			assignment[variable] = random.Intn(2) == 0
+		}
+		assert.Equal(expr.Eval(assignment), manager.Eval(f, assignment))
+	}
+}
+
+func TestRefKeepsDiagrams(t *testing.T) {
+	assert := assert.New(t)
+
+	first, _ := parseInput("a & b | c")
+	second, _ := parseInput("a + c")
+	manager := NewBDDManager()
+	f := manager.FromExpression(first)
+	g := manager.FromExpression(second)
+	manager.Ref(g)
+
+	manager.Sift(f)
+	assert.True(sameTruthTable(second, manager.ToExpression(g)))
+
+	manager.Deref(g)
+	manager.Sift(f)
+	assert.Equal(manager.NodeCount(f), manager.Statistics().Nodes)
+}
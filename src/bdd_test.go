@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBDDOperators(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test variable", "a", "a"},
+		{"test not", "!a", "!a"},
+		{"test and", "a & b", "a & b"},
+		{"test or", "a | b", "a | b"},
+		{"test xor", "a + b", "a & !b | !a & b"},
+		{"test implies", "a -> b", "!a | b"},
+		{"test equivalence", "a <-> b", "a & b | !a & !b"},
+		{"test tautology", "a | !a", "1"},
+		{"test contradiction", "a & !a", "0"},
+		{"test constant", "a & 1", "a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			manager := NewBDDManager()
+			f := manager.FromExpression(expr)
+			assert.Equal(test.expected, manager.ToExpression(f).String(), test.name)
+		})
+	}
+}
+
+func TestBDDCanonical(t *testing.T) {
+	assert := assert.New(t)
+
+	manager := NewBDDManager("a", "b", "c")
+	first, _ := parseInput("a & b | a & c")
+	second, _ := parseInput("!(!a | !b & !c)")
+	third, _ := parseInput("(b | c) & a")
+	other, _ := parseInput("a & b | c")
+
+	f := manager.FromExpression(first)
+	assert.Equal(f, manager.FromExpression(second))
+	assert.Equal(f, manager.FromExpression(third))
+	assert.NotEqual(f, manager.FromExpression(other))
+
+	// a, then b and c sharing the node of c
+	assert.Equal(5, manager.NodeCount(f))
+	assert.Equal([]string{"a", "b", "c"}, manager.Order())
+}
+
+func TestBDDRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(21))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 300; i++ {
+		expr := randomExpression(random, 5, variables)
+		manager := NewBDDManager(variables...)
+		f := manager.FromExpression(expr)
+
+		for row := 0; row < 1<<len(variables); row++ {
+			assignment := assignmentOf(row, variables)
+			assert.Equal(expr.Eval(assignment), manager.Eval(f, assignment), Print(expr, ASCII))
+		}
+
+		assert.True(sameTruthTable(expr, manager.ToExpression(f)), Print(expr, ASCII))
+		assert.Equal(big.NewInt(int64(len(mintermsOf(expr, variables)))), manager.SatCount(f), Print(expr, ASCII))
+
+		// equivalent expressions give the same node
+		assert.Equal(f, manager.FromExpression(manager.ToExpression(f)), Print(expr, ASCII))
+
+		assignment, ok := manager.AnySat(f)
+		assert.Equal(f != BDDFalse, ok)
+		if ok {
+			assert.True(expr.Eval(assignment))
+		}
+	}
+}
+
+func TestBDDManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	// the parity of 100 variables has a linear diagram
+	manager := NewBDDManager()
+	f := BDDFalse
+	for index := 0; index < 100; index++ {
+		f = manager.XOR(f, manager.Variable(fmt.Sprintf("x%d", index)))
+	}
+
+	assert.Equal(2*100+1, manager.NodeCount(f))
+	assert.Equal(new(big.Int).Lsh(big.NewInt(1), 99), manager.SatCount(f))
+	assert.Equal(BDDTrue, manager.XOR(f, manager.Not(f)))
+}
+
+func TestBDDSatCountUnusedVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	manager := NewBDDManager("a", "b", "c", "d")
+	f := manager.Variable("c")
+	assert.Equal(big.NewInt(8), manager.SatCount(f))
+	assert.Equal(big.NewInt(16), manager.SatCount(BDDTrue))
+	assert.Equal(big.NewInt(0), manager.SatCount(BDDFalse))
+}
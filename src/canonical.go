@@ -0,0 +1,169 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Maximal number of variables of the canonical forms, which list rows of the truth table
+const MAX_CANONICAL_VARIABLES = 16
+
+type NormalForm int
+
+// Normal forms printed by the Runner
+const (
+	FormDNF      NormalForm = iota // Disjunctive normal form, with ToDNF
+	FormCNF                        // Conjunctive normal form, with ToCNF
+	FormMinterms                   // Sum of minterms and Σm notation
+	FormMaxterms                   // Product of maxterms and ΠM notation
+	FormNNF                        // Negation normal form, with ToNNF
+	FormANF                        // Algebraic normal form, with ToANF
+)
+
+var normalFormNames = map[NormalForm]string{
+	FormDNF:      "dnf",
+	FormCNF:      "cnf",
+	FormMinterms: "minterms",
+	FormMaxterms: "maxterms",
+	FormNNF:      "nnf",
+	FormANF:      "anf",
+}
+
+func (form NormalForm) String() string {
+	return normalFormNames[form]
+}
+
+/*
+Return the normal forms of a comma-separated list of names (dnf, cnf, nnf, anf, minterms,
+maxterms), all of them for the name all
+*/
+func ParseNormalForms(list string) ([]NormalForm, error) {
+	forms := []NormalForm{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			return []NormalForm{FormNNF, FormDNF, FormCNF, FormANF, FormMinterms, FormMaxterms}, nil
+		}
+
+		found := false
+		for form, formName := range normalFormNames {
+			if formName == name {
+				forms, found = append(forms, form), true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown normal form %s, expected dnf, cnf, nnf, anf, minterms, maxterms or all", name)
+		}
+	}
+	return forms, nil
+}
+
+/*
+Return the rows of the truth table for which the expression is true, numbered as by
+MinimizeMinterms: the variable at index k of Variables(expr) is true when the bit k of
+the row is set
+*/
+func Minterms(expr Expression) ([]int, error) {
+	variables, err := canonicalVariables(expr)
+	if err != nil {
+		return nil, err
+	}
+	return mintermsOf(expr, variables), nil
+}
+
+/*
+Return the rows of the truth table for which the expression is false, numbered as by
+Minterms
+*/
+func Maxterms(expr Expression) ([]int, error) {
+	return Minterms(NewNotExpression(expr))
+}
+
+/*
+Return the canonical sum of products of the expression: the disjunction of its minterms,
+each one being the conjunction of all the variables, negated when they are false on the row
+*/
+func SumOfMinterms(expr Expression) (Expression, error) {
+	minterms, err := Minterms(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := Variables(expr)
+	terms := []Expression{}
+	for _, row := range minterms {
+		terms = append(terms, mintermCube(row, len(variables)).toExpression(variables))
+	}
+	return disjunctionOf(terms), nil
+}
+
+/*
+Return the canonical product of sums of the expression: the conjunction of its maxterms,
+each one being the disjunction of all the variables, negated when they are true on the row
+*/
+func ProductOfMaxterms(expr Expression) (Expression, error) {
+	maxterms, err := Maxterms(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := Variables(expr)
+	clauses := []Expression{}
+	for _, row := range maxterms {
+		literals := []Expression{}
+		for index, variable := range variables {
+			var literal Expression = NewVarExpression(variable)
+			if row&(1<<index) != 0 {
+				literal = NewNotExpression(literal)
+			}
+			literals = append(literals, literal)
+		}
+		clauses = append(clauses, disjunctionOf(literals))
+	}
+	return conjunctionOf(clauses), nil
+}
+
+/*
+Return the minterms in the notation of the textbooks, such as f(c, b, a) = Σm(1, 3, 5).
+The variables are listed from the most significant bit of the row numbers, which is the
+last variable of the expression
+*/
+func MintermNotation(expr Expression) (string, error) {
+	minterms, err := Minterms(expr)
+	if err != nil {
+		return "", err
+	}
+	return canonicalNotation(Variables(expr), "Σm", minterms), nil
+}
+
+/*
+Return the maxterms in the notation of the textbooks, such as f(c, b, a) = ΠM(0, 2, 4)
+*/
+func MaxtermNotation(expr Expression) (string, error) {
+	maxterms, err := Maxterms(expr)
+	if err != nil {
+		return "", err
+	}
+	return canonicalNotation(Variables(expr), "ΠM", maxterms), nil
+}
+
+func canonicalNotation(variables []string, symbol string, rows []int) string {
+	reversed := make([]string, len(variables))
+	for index, variable := range variables {
+		reversed[len(variables)-1-index] = variable
+	}
+
+	numbers := make([]string, len(rows))
+	for index, row := range rows {
+		numbers[index] = fmt.Sprint(row)
+	}
+	return fmt.Sprintf("f(%s) = %s(%s)", strings.Join(reversed, ", "), symbol, strings.Join(numbers, ", "))
+}
+
+func canonicalVariables(expr Expression) ([]string, error) {
+	variables := Variables(expr)
+	if len(variables) > MAX_CANONICAL_VARIABLES {
+		return nil, fmt.Errorf("the canonical forms support at most %d variables, found %d", MAX_CANONICAL_VARIABLES, len(variables))
+	}
+	return variables, nil
+}
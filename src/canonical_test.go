@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalForms(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name             string
+		input            string
+		expectedMinterms string
+		expectedMaxterms string
+		expectedSum      string
+		expectedProduct  string
+	}{
+		{"test and", "a & b", "f(b, a) = Σm(3)", "f(b, a) = ΠM(0, 1, 2)", "a & b", "(a | b) & (!a | b) & (a | !b)"},
+		{"test implies", "a -> b", "f(b, a) = Σm(0, 2, 3)", "f(b, a) = ΠM(1)", "!a & !b | !a & b | a & b", "!a | b"},
+		{"test three variables", "a & b | c", "f(c, b, a) = Σm(3, 4, 5, 6, 7)", "f(c, b, a) = ΠM(0, 1, 2)", "a & b & !c | !a & !b & c | a & !b & c | !a & b & c | a & b & c", "(a | b | c) & (!a | b | c) & (a | !b | c)"},
+		{"test tautology", "a | !a", "f(a) = Σm(0, 1)", "f(a) = ΠM()", "!a | a", "1"},
+		{"test contradiction", "a & !a", "f(a) = Σm()", "f(a) = ΠM(0, 1)", "0", "a & !a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			minterms, err := MintermNotation(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedMinterms, minterms)
+			maxterms, err := MaxtermNotation(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedMaxterms, maxterms)
+
+			sum, err := SumOfMinterms(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedSum, sum.String())
+			product, err := ProductOfMaxterms(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedProduct, product.String())
+		})
+	}
+}
+
+func TestCanonicalFormsRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(36))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 4, variables)
+		sum, err := SumOfMinterms(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, sum), Print(expr, ASCII))
+		product, err := ProductOfMaxterms(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, product), Print(expr, ASCII))
+
+		// the minterms and the maxterms split the rows of the truth table
+		minterms, _ := Minterms(expr)
+		maxterms, _ := Maxterms(expr)
+		assert.Equal(1<<len(Variables(expr)), len(minterms)+len(maxterms))
+		minimized, err := MinimizeMinterms(Variables(expr), minterms, []int{})
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, minimized), Print(expr, ASCII))
+	}
+}
+
+func TestCanonicalFormsTooManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a0 & a1 & a2 & a3 & a4 & a5 & a6 & a7 & a8 & a9 & a10 & a11 & a12 & a13 & a14 & a15 & a16")
+	_, err := SumOfMinterms(expr)
+	assert.Equal("the canonical forms support at most 16 variables, found 17", err.Error())
+}
+
+func TestParseNormalForms(t *testing.T) {
+	assert := assert.New(t)
+
+	forms, err := ParseNormalForms("minterms, DNF")
+	assert.Nil(err)
+	assert.Equal([]NormalForm{FormMinterms, FormDNF}, forms)
+
+	forms, err = ParseNormalForms("all")
+	assert.Nil(err)
+	assert.Equal([]NormalForm{FormNNF, FormDNF, FormCNF, FormANF, FormMinterms, FormMaxterms}, forms)
+
+	_, err = ParseNormalForms("dnf,xnf")
+	assert.Equal("unknown normal form xnf, expected dnf, cnf, nnf, anf, minterms, maxterms or all", err.Error())
+}
@@ -0,0 +1,441 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+/*
+Kind of a cardinality constraint
+*/
+type CardinalityKind int
+
+const (
+	AtLeast CardinalityKind = iota // At least k operands are true
+	AtMost                         // At most k operands are true
+	Exactly                        // Exactly k operands are true
+)
+
+var cardinalityKindNames = map[CardinalityKind]string{
+	AtLeast: "atleast",
+	AtMost:  "atmost",
+	Exactly: "exactly",
+}
+
+func (kind CardinalityKind) String() string {
+	return cardinalityKindNames[kind]
+}
+
+/*
+Return the kind of cardinality constraint of a function name (atleast, atmost or exactly)
+*/
+func parseCardinalityKind(name string) (CardinalityKind, bool) {
+	for kind, kindName := range cardinalityKindNames {
+		if strings.EqualFold(name, kindName) {
+			return kind, true
+		}
+	}
+	return AtLeast, false
+}
+
+// Cardinality expression API
+type CardinalityExpression struct {
+	kind     CardinalityKind
+	bound    int
+	operands []Expression
+}
+
+/*
+Create the constraint "at least", "at most" or "exactly" bound of the operands are true
+*/
+func NewCardinalityExpression(kind CardinalityKind, bound int, operands ...Expression) *CardinalityExpression {
+	return &CardinalityExpression{kind: kind, bound: bound, operands: operands}
+}
+
+func (cardinalityExpr CardinalityExpression) equal(expr Expression) bool {
+	if value, ok := expr.(*CardinalityExpression); ok {
+		return value.kind == cardinalityExpr.kind && value.bound == cardinalityExpr.bound &&
+			equalOperands(value.operands, cardinalityExpr.operands)
+	}
+
+	return false
+}
+
+func (cardinalityExpr *CardinalityExpression) Eval(variables map[string]bool) bool {
+	count := 0
+	for _, operand := range cardinalityExpr.operands {
+		if operand.Eval(variables) {
+			count++
+		}
+	}
+
+	switch cardinalityExpr.kind {
+	case AtLeast:
+		return count >= cardinalityExpr.bound
+	case AtMost:
+		return count <= cardinalityExpr.bound
+	default:
+		return count == cardinalityExpr.bound
+	}
+}
+
+func (cardinalityExpr *CardinalityExpression) Simplify() Expression {
+	// the constant operands change the bound
+	bound := cardinalityExpr.bound
+	operands := []Expression{}
+	for _, operand := range cardinalityExpr.operands {
+		simplified := operand.Simplify()
+		if value, ok := simplified.(*NumberExpression); ok {
+			bound -= value.value
+			continue
+		}
+		operands = append(operands, simplified)
+	}
+
+	count := len(operands)
+	kind := cardinalityExpr.kind
+	switch {
+	case (kind == AtLeast && bound <= 0) || (kind == AtMost && bound >= count):
+		return NewNumberExpression(1)
+	case (kind != AtMost && bound > count) || (kind != AtLeast && bound < 0):
+		return NewNumberExpression(0)
+	case kind != AtMost && bound == count:
+		// all the operands are true
+		return conjunctionOf(operands).Simplify()
+	case kind != AtLeast && bound == 0:
+		// all the operands are false
+		return NewNotExpression(disjunctionOf(operands)).Simplify()
+	case kind == AtLeast && bound == 1:
+		return disjunctionOf(operands).Simplify()
+	}
+
+	return NewCardinalityExpression(kind, bound, operands...)
+}
+
+func (cardinalityExpr CardinalityExpression) String() string {
+	return Print(&cardinalityExpr, ASCII)
+}
+
+func (cardinalityExpr *CardinalityExpression) ToDot(builder *strings.Builder, parentID string) {
+	nodeID := fmt.Sprintf("cardinality_%p", cardinalityExpr)
+	builder.WriteString(fmt.Sprintf("\"%s\" [label=\"%s %d\"];\n", nodeID, strings.ToUpper(cardinalityExpr.kind.String()), cardinalityExpr.bound))
+	if parentID != "" {
+		builder.WriteString(fmt.Sprintf(DOT_FORMAT, parentID, nodeID))
+	}
+	for _, operand := range cardinalityExpr.operands {
+		operand.ToDot(builder, nodeID)
+	}
+}
+
+/*
+Comparison of a pseudo-Boolean constraint
+*/
+type PBComparison int
+
+const (
+	GreaterOrEqual PBComparison = iota // >=
+	LessOrEqual                        // <=
+)
+
+// Pseudo-Boolean expression API
+type PseudoBooleanExpression struct {
+	coefficients []int
+	operands     []Expression
+	comparison   PBComparison
+	bound        int
+}
+
+/*
+Create the linear constraint c1 x1 + c2 x2 + ... >= bound (or <= bound), where each
+operand counts for its coefficient when it is true. The Parser only reads variables and
+negated variables with positive coefficients and bounds: Print rewrites the other
+constraints into an equivalent form that it reads
+*/
+func NewPseudoBooleanExpression(coefficients []int, operands []Expression, comparison PBComparison, bound int) *PseudoBooleanExpression {
+	return &PseudoBooleanExpression{coefficients: coefficients, operands: operands, comparison: comparison, bound: bound}
+}
+
+func (pbExpr PseudoBooleanExpression) equal(expr Expression) bool {
+	if value, ok := expr.(*PseudoBooleanExpression); ok {
+		if value.comparison != pbExpr.comparison || value.bound != pbExpr.bound || len(value.coefficients) != len(pbExpr.coefficients) {
+			return false
+		}
+		for index, coefficient := range value.coefficients {
+			if coefficient != pbExpr.coefficients[index] {
+				return false
+			}
+		}
+		return equalOperands(value.operands, pbExpr.operands)
+	}
+
+	return false
+}
+
+func (pbExpr *PseudoBooleanExpression) Eval(variables map[string]bool) bool {
+	sum := 0
+	for index, operand := range pbExpr.operands {
+		if operand.Eval(variables) {
+			sum += pbExpr.coefficients[index]
+		}
+	}
+
+	if pbExpr.comparison == GreaterOrEqual {
+		return sum >= pbExpr.bound
+	}
+	return sum <= pbExpr.bound
+}
+
+func (pbExpr *PseudoBooleanExpression) Simplify() Expression {
+	// the constant operands change the bound, the operands without weight are removed
+	bound := pbExpr.bound
+	coefficients, operands := []int{}, []Expression{}
+	minSum, maxSum := 0, 0
+	unit := true
+	for index, operand := range pbExpr.operands {
+		coefficient := pbExpr.coefficients[index]
+		simplified := operand.Simplify()
+		if value, ok := simplified.(*NumberExpression); ok {
+			bound -= coefficient * value.value
+			continue
+		}
+		if coefficient == 0 {
+			continue
+		}
+
+		coefficients, operands = append(coefficients, coefficient), append(operands, simplified)
+		unit = unit && coefficient == 1
+		if coefficient < 0 {
+			minSum += coefficient
+		} else {
+			maxSum += coefficient
+		}
+	}
+
+	greater := pbExpr.comparison == GreaterOrEqual
+	switch {
+	case (greater && bound <= minSum) || (!greater && bound >= maxSum):
+		return NewNumberExpression(1)
+	case (greater && bound > maxSum) || (!greater && bound < minSum):
+		return NewNumberExpression(0)
+	case unit && greater:
+		return NewCardinalityExpression(AtLeast, bound, operands...).Simplify()
+	case unit:
+		return NewCardinalityExpression(AtMost, bound, operands...).Simplify()
+	}
+
+	return NewPseudoBooleanExpression(coefficients, operands, pbExpr.comparison, bound)
+}
+
+func (pbExpr PseudoBooleanExpression) String() string {
+	return Print(&pbExpr, ASCII)
+}
+
+func (pbExpr *PseudoBooleanExpression) ToDot(builder *strings.Builder, parentID string) {
+	nodeID := fmt.Sprintf("pb_%p", pbExpr)
+	comparison := ">="
+	if pbExpr.comparison == LessOrEqual {
+		comparison = "<="
+	}
+	builder.WriteString(fmt.Sprintf("\"%s\" [label=\"SUM %s %d\"];\n", nodeID, comparison, pbExpr.bound))
+	if parentID != "" {
+		builder.WriteString(fmt.Sprintf(DOT_FORMAT, parentID, nodeID))
+	}
+
+	// the coefficients other than 1 are nodes between the sum and the operands
+	for index, operand := range pbExpr.operands {
+		if coefficient := pbExpr.coefficients[index]; coefficient != 1 {
+			coefficientID := fmt.Sprintf("%s_%d", nodeID, index)
+			builder.WriteString(fmt.Sprintf("\"%s\" [label=\"x%d\"];\n", coefficientID, coefficient))
+			builder.WriteString(fmt.Sprintf(DOT_FORMAT, nodeID, coefficientID))
+			operand.ToDot(builder, coefficientID)
+		} else {
+			operand.ToDot(builder, nodeID)
+		}
+	}
+}
+
+func equalOperands(first, second []Expression) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for index, operand := range first {
+		if !operand.equal(second[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return the conjunction of the expressions, 1 when there is none
+*/
+func conjunctionOf(exprs []Expression) Expression {
+	if len(exprs) == 0 {
+		return NewNumberExpression(1)
+	}
+
+	expr := exprs[0]
+	for _, operand := range exprs[1:] {
+		expr = NewAndExpression(expr, operand)
+	}
+	return expr
+}
+
+/*
+Return the disjunction of the expressions, 0 when there is none
+*/
+func disjunctionOf(exprs []Expression) Expression {
+	if len(exprs) == 0 {
+		return NewNumberExpression(0)
+	}
+
+	expr := exprs[0]
+	for _, operand := range exprs[1:] {
+		expr = NewOrExpression(expr, operand)
+	}
+	return expr
+}
+
+/*
+Linear constraint lower <= w1 x1 + w2 x2 + ... <= upper with positive weights, to which
+the cardinality and pseudo-Boolean constraints are normalized before being encoded
+*/
+type linearConstraint struct {
+	operands []Expression
+	weights  []int
+	lower    int // Always true when lower <= 0
+	upper    int // Always true when upper >= the sum of the weights
+}
+
+/*
+Return the sum of the weights, which is the maximal value of the sum
+*/
+func (constraint linearConstraint) total() int {
+	total := 0
+	for _, weight := range constraint.weights {
+		total += weight
+	}
+	return total
+}
+
+/*
+Return the linear constraint of a cardinality or pseudo-Boolean expression. A negative
+coefficient c of an operand x is replaced by the positive coefficient -c of !x, since
+c x = c + (-c) !x
+*/
+func linearConstraintOf(expr Expression) (linearConstraint, bool) {
+	switch value := expr.(type) {
+	case *CardinalityExpression:
+		constraint := linearConstraint{operands: value.operands, lower: math.MinInt, upper: math.MaxInt}
+		for range value.operands {
+			constraint.weights = append(constraint.weights, 1)
+		}
+		if value.kind != AtMost {
+			constraint.lower = value.bound
+		}
+		if value.kind != AtLeast {
+			constraint.upper = value.bound
+		}
+		return constraint, true
+	case *PseudoBooleanExpression:
+		constraint := linearConstraint{lower: math.MinInt, upper: math.MaxInt}
+		offset := 0
+		for index, operand := range value.operands {
+			coefficient := value.coefficients[index]
+			switch {
+			case coefficient > 0:
+				constraint.operands = append(constraint.operands, operand)
+				constraint.weights = append(constraint.weights, coefficient)
+			case coefficient < 0:
+				offset += coefficient
+				constraint.operands = append(constraint.operands, NewNotExpression(operand))
+				constraint.weights = append(constraint.weights, -coefficient)
+			}
+		}
+
+		if value.comparison == GreaterOrEqual {
+			constraint.lower = value.bound - offset
+		} else {
+			constraint.upper = value.bound - offset
+		}
+		return constraint, true
+	default:
+		return linearConstraint{}, false
+	}
+}
+
+/*
+Rewrite a cardinality or pseudo-Boolean expression with AND, OR and NOT, as the decision
+tree of its operands where the subtrees of the same operand and partial sum are shared
+*/
+func expandLinearConstraint(expr Expression) Expression {
+	constraint, _ := linearConstraintOf(expr)
+	total := constraint.total()
+
+	// suffixes[i] is the sum of the weights from the operand i
+	suffixes := make([]int, len(constraint.weights)+1)
+	for index := len(constraint.weights) - 1; index >= 0; index-- {
+		suffixes[index] = suffixes[index+1] + constraint.weights[index]
+	}
+
+	type key struct{ index, bound int }
+	cache := make(map[key]Expression)
+	// atLeast returns the expression of "the operands from index weigh at least bound"
+	var atLeast func(index, bound int) Expression
+	atLeast = func(index, bound int) Expression {
+		if bound <= 0 {
+			return NewNumberExpression(1)
+		}
+		if suffixes[index] < bound {
+			return NewNumberExpression(0)
+		}
+		if expr, ok := cache[key{index, bound}]; ok {
+			return expr
+		}
+
+		operand := constraint.operands[index]
+		var expr Expression
+		switch high, low := atLeast(index+1, bound-constraint.weights[index]), atLeast(index+1, bound); {
+		case isConstant(high, 1) && isConstant(low, 0):
+			expr = operand
+		case isConstant(high, 1):
+			expr = NewOrExpression(operand, low)
+		case isConstant(low, 0):
+			expr = NewAndExpression(operand, high)
+		default:
+			expr = NewOrExpression(NewAndExpression(operand, high), NewAndExpression(NewNotExpression(operand), low))
+		}
+		cache[key{index, bound}] = expr
+		return expr
+	}
+
+	parts := []Expression{}
+	if constraint.lower > 0 {
+		parts = append(parts, atLeast(0, constraint.lower))
+	}
+	if constraint.upper < total {
+		parts = append(parts, NewNotExpression(atLeast(0, constraint.upper+1)))
+	}
+	return conjunctionOf(parts)
+}
+
+func isConstant(expr Expression, value int) bool {
+	number, ok := expr.(*NumberExpression)
+	return ok && number.value == value
+}
+
+/*
+Return true if the expression contains cardinality or pseudo-Boolean constraints
+*/
+func hasLinearConstraints(expr Expression) bool {
+	if _, ok := linearConstraintOf(expr); ok {
+		return true
+	}
+	for _, operand := range operands(expr) {
+		if hasLinearConstraints(operand) {
+			return true
+		}
+	}
+	return false
+}
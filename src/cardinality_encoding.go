@@ -0,0 +1,395 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type CardinalityEncoding int
+
+/*
+Encodings of the cardinality constraints into clauses. Each one counts the true operands
+in unary: its k-th output is equivalent to "at least k operands are true", so that the
+auxiliary variables are determined by the operands and the models of the CNF can be
+counted. The pseudo-Boolean constraints with other coefficients than 1 are encoded with
+a generalized totalizer whatever the encoding, whose outputs are the sums that the
+operands can reach: it does not grow with the coefficients as a unary counter of c
+copies of each operand would
+*/
+const (
+	Totalizer         CardinalityEncoding = iota // Binary tree of unary adders, O(n log n) variables
+	SequentialCounter                            // Chain of unary counters, O(n k) variables
+	SortingNetwork                               // Odd-even merge sort of the operands, O(n log² n) variables
+)
+
+var cardinalityEncodingNames = map[CardinalityEncoding]string{
+	Totalizer:         "totalizer",
+	SequentialCounter: "counter",
+	SortingNetwork:    "sorting",
+}
+
+func (encoding CardinalityEncoding) String() string {
+	return cardinalityEncodingNames[encoding]
+}
+
+/*
+Return the cardinality encoding associated to a name (totalizer, counter or sorting)
+*/
+func ParseCardinalityEncoding(name string) (CardinalityEncoding, error) {
+	for encoding, encodingName := range cardinalityEncodingNames {
+		if strings.EqualFold(name, encodingName) {
+			return encoding, nil
+		}
+	}
+
+	return Totalizer, fmt.Errorf("unknown cardinality encoding %s, expected totalizer, counter or sorting", name)
+}
+
+/*
+Add the clauses of t <-> (lower <= sum of the operands <= upper)
+*/
+func (encoder *tseitinEncoder) encodeLinear(expr Expression, literal Literal, polarity int) {
+	constraint, ok := linearConstraintOf(expr)
+	if !ok {
+		panic(fmt.Sprintf("%s is not a cardinality or pseudo-Boolean constraint", expr))
+	}
+	constraint = dividedByGCD(constraint)
+	total := constraint.total()
+
+	// only the outputs "at least lower" and "at least upper + 1" are needed, so the
+	// counters stop at the largest one, and larger sums count as this limit
+	limit := 0
+	if constraint.lower > 0 {
+		limit = min(constraint.lower, total)
+	}
+	if constraint.upper < total {
+		limit = max(limit, constraint.upper+1)
+	}
+
+	inputs := []Literal{}
+	for _, operand := range constraint.operands {
+		inputs = append(inputs, encoder.literal(operand, bothPolarities))
+	}
+
+	atLeast := func(bound int) Literal {
+		if bound <= 0 {
+			return encoder.constant(true)
+		}
+		if bound > constraint.total() {
+			return encoder.constant(false)
+		}
+
+		// the smallest sum reaching the bound
+		counter := encoder.counter(inputs, constraint.weights, limit)
+		return counter.outputs[sort.SearchInts(counter.sums, bound)]
+	}
+
+	conditions := []Literal{}
+	if constraint.lower > 0 {
+		conditions = append(conditions, atLeast(constraint.lower))
+	}
+	if constraint.upper < total {
+		conditions = append(conditions, atLeast(constraint.upper+1).Not())
+	}
+	encoder.addJunctionClauses(literal, conditions, true, polarity)
+}
+
+/*
+Divide the weights of a linear constraint by their greatest common divisor, rounding the
+bounds so that the same sums are accepted, which makes the counters smaller
+*/
+func dividedByGCD(constraint linearConstraint) linearConstraint {
+	divisor := 0
+	for _, weight := range constraint.weights {
+		divisor = gcd(divisor, weight)
+	}
+	if divisor <= 1 {
+		return constraint
+	}
+
+	divided := linearConstraint{operands: constraint.operands, lower: constraint.lower, upper: constraint.upper}
+	for _, weight := range constraint.weights {
+		divided.weights = append(divided.weights, weight/divisor)
+	}
+	if constraint.lower > 0 {
+		divided.lower = (constraint.lower-1)/divisor + 1
+	}
+	if constraint.upper < constraint.total() {
+		divided.upper = floorDivision(constraint.upper, divisor)
+	}
+	return divided
+}
+
+func gcd(first, second int) int {
+	for second != 0 {
+		first, second = second, first%second
+	}
+	return first
+}
+
+func floorDivision(value, divisor int) int {
+	if value < 0 {
+		return -((-value + divisor - 1) / divisor)
+	}
+	return value / divisor
+}
+
+/*
+Outputs of a counter by increasing sum: the output k is equivalent to "the sum of the
+weights of the true inputs is at least sums[k]"
+*/
+type counterOutputs struct {
+	sums    []int
+	outputs []Literal
+}
+
+/*
+Return the outputs of the counter of the weighted inputs, up to the limit. The inputs of
+weight 1 are counted with the encoding of the encoder, the other ones with a generalized
+totalizer. The counter of the same inputs is only built once
+*/
+func (encoder *tseitinEncoder) counter(inputs []Literal, weights []int, limit int) counterOutputs {
+	key := fmt.Sprint(limit, inputs, weights)
+	if counter, ok := encoder.counters[key]; ok {
+		return counter
+	}
+
+	unit := true
+	for _, weight := range weights {
+		unit = unit && weight == 1
+	}
+
+	var counter counterOutputs
+	switch {
+	case !unit:
+		counter.sums, counter.outputs = encoder.generalizedTotalizer(inputs, weights, limit)
+	case encoder.cardinality == SequentialCounter:
+		counter.outputs = encoder.sequentialCounter(inputs, limit)
+	case encoder.cardinality == SortingNetwork:
+		counter.outputs = encoder.sortingNetwork(inputs, limit)
+	default:
+		counter.outputs = encoder.totalizer(inputs, limit)
+	}
+	if unit {
+		for index := range counter.outputs {
+			counter.sums = append(counter.sums, index+1)
+		}
+	}
+
+	encoder.counters[key] = counter
+	return counter
+}
+
+/*
+Sum the weighted inputs with a binary tree, each node having an output for each sum that
+its true inputs can reach, and returning these sums in increasing order with their
+outputs. The sums are saturated at the limit, so that a node has at most limit outputs
+and its clauses are quadratic in the limit instead of in the weights
+*/
+func (encoder *tseitinEncoder) generalizedTotalizer(inputs []Literal, weights []int, limit int) ([]int, []Literal) {
+	if len(inputs) == 1 {
+		return []int{min(weights[0], limit)}, inputs
+	}
+
+	middle := len(inputs) / 2
+	leftSums, left := encoder.generalizedTotalizer(inputs[:middle], weights[:middle], limit)
+	rightSums, right := encoder.generalizedTotalizer(inputs[middle:], weights[middle:], limit)
+
+	// the sum 0 of a child, reached when none of its inputs is true, has no output
+	leftSums, rightSums = append([]int{0}, leftSums...), append([]int{0}, rightSums...)
+	reached := make(map[int]bool)
+	for _, leftSum := range leftSums {
+		for _, rightSum := range rightSums {
+			if sum := min(leftSum+rightSum, limit); sum > 0 {
+				reached[sum] = true
+			}
+		}
+	}
+
+	sums := []int{}
+	for sum := range reached {
+		sums = append(sums, sum)
+	}
+	sort.Ints(sums)
+	outputs := make([]Literal, len(sums))
+	for index := range outputs {
+		outputs[index] = encoder.cnf.NewAuxiliary()
+		if index > 0 {
+			// a sum is at least the previous ones
+			encoder.cnf.AddClause(outputs[index].Not(), outputs[index-1])
+		}
+	}
+
+	// the sum is at least i + j when the left one is at least i and the right one at least
+	// j, and below the next sum when the left one is below i' and the right one below j',
+	// the sums following i and j
+	for i, leftSum := range leftSums {
+		for j, rightSum := range rightSums {
+			sum := min(leftSum+rightSum, limit)
+			if sum > 0 {
+				clause := Clause{outputs[sort.SearchInts(sums, sum)]}
+				if i > 0 {
+					clause = append(clause, left[i-1].Not())
+				}
+				if j > 0 {
+					clause = append(clause, right[j-1].Not())
+				}
+				encoder.cnf.AddClause(clause...)
+			}
+
+			if next := sort.SearchInts(sums, sum+1); sum < limit && next < len(sums) {
+				clause := Clause{outputs[next].Not()}
+				if i < len(left) {
+					clause = append(clause, left[i])
+				}
+				if j < len(right) {
+					clause = append(clause, right[j])
+				}
+				encoder.cnf.AddClause(clause...)
+			}
+		}
+	}
+	return sums, outputs
+}
+
+/*
+Count the inputs one after the other: the row of an input holds the counts of the
+inputs up to it, and the count k is reached when it was reached by the previous row, or
+when the previous row reached k-1 and the input is true
+*/
+func (encoder *tseitinEncoder) sequentialCounter(inputs []Literal, limit int) []Literal {
+	outputs := []Literal{}
+	for _, input := range inputs {
+		row := make([]Literal, min(len(outputs)+1, limit))
+		for count := range row {
+			increment := input
+			if count > 0 {
+				increment = encoder.define(true, input, outputs[count-1])
+			}
+			if count < len(outputs) {
+				row[count] = encoder.define(false, outputs[count], increment)
+			} else {
+				row[count] = increment
+			}
+		}
+		outputs = row
+	}
+	return outputs
+}
+
+/*
+Count the inputs with a binary tree, each node adding the unary counts of its children.
+The counts are saturated at the limit
+*/
+func (encoder *tseitinEncoder) totalizer(inputs []Literal, limit int) []Literal {
+	if len(inputs) == 1 {
+		return inputs
+	}
+
+	middle := len(inputs) / 2
+	left, right := encoder.totalizer(inputs[:middle], limit), encoder.totalizer(inputs[middle:], limit)
+	outputs := make([]Literal, min(len(left)+len(right), limit))
+	for index := range outputs {
+		outputs[index] = encoder.cnf.NewAuxiliary()
+	}
+
+	// the count is at least i + j when the left one is at least i and the right one at
+	// least j, and at most i + j when the left one is at most i and the right one at most j
+	for i := 0; i <= len(left); i++ {
+		for j := 0; j <= len(right); j++ {
+			if sum := min(i+j, len(outputs)); sum > 0 {
+				clause := Clause{outputs[sum-1]}
+				if i > 0 {
+					clause = append(clause, left[i-1].Not())
+				}
+				if j > 0 {
+					clause = append(clause, right[j-1].Not())
+				}
+				encoder.cnf.AddClause(clause...)
+			}
+
+			if i+j < len(outputs) {
+				clause := Clause{outputs[i+j].Not()}
+				if i < len(left) {
+					clause = append(clause, left[i])
+				}
+				if j < len(right) {
+					clause = append(clause, right[j])
+				}
+				encoder.cnf.AddClause(clause...)
+			}
+		}
+	}
+	return outputs
+}
+
+/*
+Sort the inputs with Batcher's odd-even merge sort, the true values first, so that the
+output k is true when at least k+1 inputs are. The inputs are padded with false values
+up to a power of two, and each comparator outputs the OR and the AND of its inputs
+*/
+func (encoder *tseitinEncoder) sortingNetwork(inputs []Literal, limit int) []Literal {
+	size := 1
+	for size < len(inputs) {
+		size *= 2
+	}
+
+	values := append([]Literal{}, inputs...)
+	for len(values) < size {
+		values = append(values, encoder.constant(false))
+	}
+
+	for p := 1; p < size; p *= 2 {
+		for k := p; k >= 1; k /= 2 {
+			for j := k % p; j+k < size; j += 2 * k {
+				for i := 0; i < min(k, size-j-k); i++ {
+					if (i+j)/(2*p) == (i+j+k)/(2*p) {
+						values[i+j], values[i+j+k] = encoder.compare(values[i+j], values[i+j+k])
+					}
+				}
+			}
+		}
+	}
+	return values[:min(len(inputs), limit)]
+}
+
+/*
+Return the maximum and the minimum of two literals
+*/
+func (encoder *tseitinEncoder) compare(first, second Literal) (Literal, Literal) {
+	// the padding of the sorting network, 0 when there is none
+	falseLiteral := encoder.trueLiteral.Not()
+	switch {
+	case second == falseLiteral:
+		return first, second
+	case first == falseLiteral:
+		return second, first
+	default:
+		return encoder.define(false, first, second), encoder.define(true, first, second)
+	}
+}
+
+/*
+Return a new auxiliary literal equivalent to the AND (or the OR) of the operands
+*/
+func (encoder *tseitinEncoder) define(isAnd bool, operands ...Literal) Literal {
+	literal := encoder.cnf.NewAuxiliary()
+	encoder.addJunctionClauses(literal, operands, isAnd, bothPolarities)
+	return literal
+}
+
+/*
+Return a literal with a constant value, defined by a unit clause the first time it is used
+*/
+func (encoder *tseitinEncoder) constant(value bool) Literal {
+	if encoder.trueLiteral == 0 {
+		encoder.trueLiteral = encoder.cnf.NewAuxiliary()
+		encoder.cnf.AddClause(encoder.trueLiteral)
+	}
+
+	if value {
+		return encoder.trueLiteral
+	}
+	return encoder.trueLiteral.Not()
+}
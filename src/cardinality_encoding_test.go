@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var cardinalityEncodings = []CardinalityEncoding{Totalizer, SequentialCounter, SortingNetwork}
+
+func TestParseCardinalityEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, encoding := range cardinalityEncodings {
+		parsed, err := ParseCardinalityEncoding(encoding.String())
+		assert.Nil(err)
+		assert.Equal(encoding, parsed)
+	}
+
+	_, err := ParseCardinalityEncoding("adder")
+	assert.Equal("unknown cardinality encoding adder, expected totalizer, counter or sorting", err.Error())
+}
+
+func TestCardinalityEncodings(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(34))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 150; i++ {
+		expr := randomExpressionWithConstraints(random, 2, variables)
+		expected := big.NewInt(int64(len(mintermsOf(expr, Variables(expr)))))
+
+		for _, encoding := range cardinalityEncodings {
+			// the auxiliary variables are determined by the operands, so the count is kept
+			cnf := TseitinCNFWithOptions(expr, CNFOptions{Cardinality: encoding})
+			assert.Equal(expected, cnf.CountModels(), fmt.Sprintf("%s %s", encoding, expr))
+
+			cnf = TseitinCNFWithOptions(expr, CNFOptions{PolarityAware: true, Cardinality: encoding})
+			model, ok := cnf.Solve()
+			assert.Equal(expected.Sign() > 0, ok, fmt.Sprintf("%s %s", encoding, expr))
+			if ok {
+				assert.True(expr.Eval(model), fmt.Sprintf("%s %s", encoding, expr))
+			}
+		}
+	}
+}
+
+func TestCardinalityEncodingsLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	// exactly 4 of 12 variables, with C(12, 4) models
+	operands := []Expression{}
+	for index := 0; index < 12; index++ {
+		operands = append(operands, NewVarExpression(fmt.Sprintf("x%d", index)))
+	}
+	expr := NewCardinalityExpression(Exactly, 8, operands...)
+	expected := new(big.Int).Binomial(12, 4)
+
+	for _, encoding := range cardinalityEncodings {
+		cnf := TseitinCNFWithOptions(expr, CNFOptions{Cardinality: encoding})
+		assert.Equal(expected, cnf.CountModels(), encoding.String())
+	}
+
+	// the weights above the bound count as the bound
+	pb, _ := parseInput("100a + 50b + 30c + 20d >= 70")
+	for _, encoding := range cardinalityEncodings {
+		cnf := TseitinCNFWithOptions(pb, CNFOptions{Cardinality: encoding})
+		assert.Equal(big.NewInt(int64(len(mintermsOf(pb, Variables(pb))))), cnf.CountModels(), encoding.String())
+		assert.Less(len(cnf.Variables), 200, encoding.String())
+	}
+
+	// the size of the encoding does not grow with the coefficients
+	pb, _ = parseInput("1000a + 1001b + 999c + 1d >= 1500 & 1003a + 997b + 1000c + 2d <= 2001")
+	for _, encoding := range cardinalityEncodings {
+		cnf := TseitinCNFWithOptions(pb, CNFOptions{Cardinality: encoding})
+		assert.Equal(big.NewInt(int64(len(mintermsOf(pb, Variables(pb))))), cnf.CountModels(), encoding.String())
+		assert.Less(len(cnf.Variables), 50, encoding.String())
+		assert.Less(len(cnf.Clauses), 200, encoding.String())
+	}
+}
+
+func TestWriteDIMACSWithEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("atmost(1, a, b, c) & (a | b)")
+	for _, encoding := range cardinalityEncodings {
+		var buffer bytes.Buffer
+		assert.Nil(WriteDIMACSWithEncoding(&buffer, expr, encoding))
+
+		cnf, err := ReadDIMACSCNF(&buffer)
+		assert.Nil(err)
+		assert.Equal(big.NewInt(2), cnf.CountModels(), encoding.String())
+	}
+}
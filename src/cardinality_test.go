@@ -0,0 +1,311 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return a random cardinality or pseudo-Boolean constraint over random expressions, with
+bounds that can be out of the range of the sum
+*/
+func randomLinearConstraint(random *rand.Rand, variables []string) Expression {
+	operands := []Expression{}
+	for range 1 + random.Intn(4) {
+		operands = append(operands, randomExpression(random, 1, variables))
+	}
+
+	if random.Intn(2) == 0 {
+		kind := CardinalityKind(random.Intn(3))
+		return NewCardinalityExpression(kind, random.Intn(len(operands)+3)-1, operands...)
+	}
+
+	coefficients := []int{}
+	for range operands {
+		coefficients = append(coefficients, random.Intn(8)-2)
+	}
+	return NewPseudoBooleanExpression(coefficients, operands, PBComparison(random.Intn(2)), random.Intn(12)-3)
+}
+
+/*
+Return a random expression whose operands can be linear constraints
+*/
+func randomExpressionWithConstraints(random *rand.Rand, depth int, variables []string) Expression {
+	if depth == 0 || random.Intn(3) == 0 {
+		return randomLinearConstraint(random, variables)
+	}
+
+	left := randomExpressionWithConstraints(random, depth-1, variables)
+	right := randomExpressionWithConstraints(random, depth-1, variables)
+	switch random.Intn(4) {
+	case 0:
+		return NewNotExpression(left)
+	case 1:
+		return NewAndExpression(left, right)
+	case 2:
+		return NewOrExpression(left, right)
+	default:
+		return NewXORExpression(left, right)
+	}
+}
+
+func TestCardinalityEval(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewVarExpression("a"), NewVarExpression("b"), NewVarExpression("c")
+
+	tests := []struct {
+		name      string
+		expr      Expression
+		variables map[string]bool
+		expected  bool
+	}{
+		{"test at least reached", NewCardinalityExpression(AtLeast, 2, a, b, c), map[string]bool{"a": true, "c": true}, true},
+		{"test at least not reached", NewCardinalityExpression(AtLeast, 2, a, b, c), map[string]bool{"b": true}, false},
+		{"test at most", NewCardinalityExpression(AtMost, 1, a, b, c), map[string]bool{"c": true}, true},
+		{"test at most exceeded", NewCardinalityExpression(AtMost, 1, a, b, c), map[string]bool{"a": true, "b": true}, false},
+		{"test exactly", NewCardinalityExpression(Exactly, 2, a, NewNotExpression(b), c), map[string]bool{"a": true}, true},
+		{"test exactly without operand", NewCardinalityExpression(Exactly, 0), map[string]bool{}, true},
+		{"test weighted sum", NewPseudoBooleanExpression([]int{2, 3, 1}, []Expression{a, b, c}, GreaterOrEqual, 4), map[string]bool{"b": true, "c": true}, true},
+		{"test weighted sum too small", NewPseudoBooleanExpression([]int{2, 3, 1}, []Expression{a, b, c}, GreaterOrEqual, 4), map[string]bool{"a": true, "c": true}, false},
+		{"test weighted sum at most", NewPseudoBooleanExpression([]int{2, 3}, []Expression{a, b}, LessOrEqual, 2), map[string]bool{"a": true}, true},
+		{"test negative coefficient", NewPseudoBooleanExpression([]int{-2, 1}, []Expression{a, b}, GreaterOrEqual, 0), map[string]bool{"a": true, "b": true}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(test.expected, test.expr.Eval(test.variables), test.name)
+		})
+	}
+}
+
+func TestCardinalitySimplify(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test at least 0", "atleast(0, a, b)", "1"},
+		{"test at most the count", "atmost(2, a, b)", "1"},
+		{"test bound above the count", "exactly(3, a, b)", "0"},
+		{"test all true", "atleast(2, a, b)", "a & b"},
+		{"test all false", "atmost(0, a, b)", "!a & !b"},
+		{"test at least one", "atleast(1, a, b, c)", "a | b | c"},
+		{"test constant operands", "atmost(1, a, 1, b & 1)", "!a & !b"},
+		{"test unchanged", "atmost(1, a, b, c)", "atmost(1, a, b, c)"},
+		{"test unit coefficients", "a + b + c >= 2", "atleast(2, a, b, c)"},
+		{"test weighted sum", "2a + 3b + c >= 4", "2a + 3b + c >= 4"},
+		{"test unreachable sum", "2a + b >= 4", "0"},
+		{"test sum always below", "2a + b <= 3", "1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+			simplified := expr.Simplify()
+			assert.Equal(test.expected, simplified.String(), test.name)
+			assert.True(sameTruthTable(expr, simplified), test.name)
+		})
+	}
+}
+
+func TestCardinalitySimplifyRandom(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(31))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 300; i++ {
+		expr := randomExpressionWithConstraints(random, 2, variables)
+		assert.True(sameTruthTable(expr, expr.Simplify()), expr.String())
+	}
+}
+
+func TestCardinalityParsePrint(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewVarExpression("a"), NewVarExpression("b"), NewVarExpression("c")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected Expression
+		printed  string
+	}{
+		{"test at most", "atmost(1, a, b, c)", NewCardinalityExpression(AtMost, 1, a, b, c), "atmost(1, a, b, c)"},
+		{"test case-insensitive name", "AtLeast(2,a,b|c)", NewCardinalityExpression(AtLeast, 2, a, NewOrExpression(b, c)), "atleast(2, a, b | c)"},
+		{"test without operand", "exactly(0)", NewCardinalityExpression(Exactly, 0), "exactly(0)"},
+		{"test inside an expression", "!exactly(1, a, b) & c", NewAndExpression(NewNotExpression(NewCardinalityExpression(Exactly, 1, a, b)), c), "!exactly(1, a, b) & c"},
+		{"test weighted sum", "2a + 3b + c >= 4", NewPseudoBooleanExpression([]int{2, 3, 1}, []Expression{a, b, c}, GreaterOrEqual, 4), "2a + 3b + c >= 4"},
+		{"test negated term", "a + 3!b <= 2", NewPseudoBooleanExpression([]int{1, 3}, []Expression{a, NewNotExpression(b)}, LessOrEqual, 2), "a + 3!b <= 2"},
+		{"test unicode comparison", "2 a ⊕ b ≥ 1", NewPseudoBooleanExpression([]int{2, 1}, []Expression{a, b}, GreaterOrEqual, 1), "2a + b >= 1"},
+		{"test negated sum", "!(a + b >= 1)", NewNotExpression(NewPseudoBooleanExpression([]int{1, 1}, []Expression{a, b}, GreaterOrEqual, 1)), "!(a + b >= 1)"},
+		{"test sum in an expression", "c & a + b <= 1 | c", NewOrExpression(NewAndExpression(c, NewPseudoBooleanExpression([]int{1, 1}, []Expression{a, b}, LessOrEqual, 1)), c), "c & (a + b <= 1) | c"},
+		{"test sum tighter than AND", "c & a + b >= 1", NewAndExpression(c, NewPseudoBooleanExpression([]int{1, 1}, []Expression{a, b}, GreaterOrEqual, 1)), "c & (a + b >= 1)"},
+		{"test + is still a XOR", "a + b", NewXORExpression(a, b), "a + b"},
+		{"test XOR before a sum", "a + b & c + a >= 1", NewXORExpression(a, NewAndExpression(b, NewPseudoBooleanExpression([]int{1, 1}, []Expression{c, a}, GreaterOrEqual, 1))), "a + b & (c + a >= 1)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+			if err != nil {
+				return
+			}
+			assert.True(test.expected.equal(expr), expr.String())
+			assert.Equal(test.printed, Print(expr, ASCII))
+
+			parsed, err := parseInput(Print(expr, Unicode))
+			assert.Nil(err)
+			assert.True(expr.equal(parsed), Print(expr, Unicode))
+		})
+	}
+}
+
+func TestPrintLinearConstraintsRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewVarExpression("a"), NewVarExpression("b"), NewVarExpression("c")
+
+	tests := []struct {
+		name    string
+		expr    Expression
+		printed string
+	}{
+		{"test double negation", NewPseudoBooleanExpression([]int{1, 1}, []Expression{NewNotExpression(NewNotExpression(c)), a}, LessOrEqual, 1), "c + a <= 1"},
+		{"test negative coefficient", NewPseudoBooleanExpression([]int{2, -3}, []Expression{a, b}, GreaterOrEqual, 1), "2a + 3!b >= 4"},
+		{"test negated operand with a negative coefficient", NewPseudoBooleanExpression([]int{-1, 1}, []Expression{NewNotExpression(a), b}, LessOrEqual, 0), "a + b <= 1"},
+		{"test zero coefficient", NewPseudoBooleanExpression([]int{0, 2}, []Expression{a, b}, GreaterOrEqual, 1), "2b >= 1"},
+		{"test negative bound always true", NewPseudoBooleanExpression([]int{1, 1}, []Expression{a, b}, GreaterOrEqual, -3), "a + b >= 0"},
+		{"test negative bound always false", NewPseudoBooleanExpression([]int{1, 1}, []Expression{a, b}, LessOrEqual, -3), "0"},
+		{"test expression operand", NewPseudoBooleanExpression([]int{2, 1}, []Expression{NewAndExpression(a, b), c}, GreaterOrEqual, 2), "a & b"},
+		{"test expanded operand", NewAndExpression(c, NewPseudoBooleanExpression([]int{2, 1}, []Expression{NewOrExpression(a, b), c}, LessOrEqual, 1)), "c & (!a & !b)"},
+		{"test cardinality negative bound", NewCardinalityExpression(AtLeast, -1, a, b), "atleast(0, a, b)"},
+		{"test cardinality negative upper bound", NewCardinalityExpression(AtMost, -1, a, b), "0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printed := Print(test.expr, ASCII)
+			assert.Equal(test.printed, printed)
+
+			parsed, err := parseInput(printed)
+			assert.Nil(err)
+			if err == nil {
+				assert.True(sameTruthTable(test.expr, parsed), printed)
+			}
+		})
+	}
+
+	random := rand.New(rand.NewSource(11))
+	variables := []string{"a", "b", "c"}
+	for i := 0; i < 300; i++ {
+		expr := randomExpressionWithConstraints(random, 2, variables)
+		for _, dialect := range []Dialect{ASCII, Unicode, Keywords} {
+			printed := Print(expr, dialect)
+			parsed, err := parseInput(printed)
+			assert.Nil(err, printed)
+			if err == nil {
+				assert.True(sameTruthTable(expr, parsed), printed)
+			}
+		}
+	}
+}
+
+func TestParseLongXORChain(t *testing.T) {
+	assert := assert.New(t)
+
+	// each operand of the chain could start a pseudo-Boolean sum, which is only scanned once
+	variables := []string{}
+	for index := 0; index < 5000; index++ {
+		variables = append(variables, fmt.Sprintf("a%d", index))
+	}
+
+	tokens, err := NewLexer(strings.Join(variables, " + ")).Tokenize()
+	assert.Nil(err)
+
+	start := time.Now()
+	expr, err := NewParser(tokens).Parse()
+	assert.Nil(err)
+	assert.IsType(&XORExpression{}, expr)
+	assert.Less(time.Since(start), time.Second)
+}
+
+func TestCardinalityToDot(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("atmost(2, a, b) | 3a + b >= 2")
+	var builder strings.Builder
+	expr.ToDot(&builder, "")
+	dot := builder.String()
+	assert.Contains(dot, "label=\"ATMOST 2\"")
+	assert.Contains(dot, "label=\"SUM >= 2\"")
+	assert.Contains(dot, "label=\"x3\"")
+}
+
+func TestExpandLinearConstraint(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(32))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 300; i++ {
+		expr := randomLinearConstraint(random, variables)
+		expanded := expandLinearConstraint(expr)
+		assert.False(hasLinearConstraints(expanded), expr.String())
+		assert.True(sameTruthTable(expr, expanded), expr.String())
+	}
+}
+
+func TestLinearConstraintsConversions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(33))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpressionWithConstraints(random, 2, variables)
+
+		cnf, err := ToCNF(expr)
+		assert.Nil(err)
+		manager := NewBDDManager(variables...)
+		f := manager.FromExpression(expr)
+		for row := 0; row < 1<<len(variables); row++ {
+			assignment := assignmentOf(row, variables)
+			assert.Equal(expr.Eval(assignment), cnf.Eval(assignment), expr.String())
+			assert.Equal(expr.Eval(assignment), manager.Eval(f, assignment), expr.String())
+		}
+	}
+}
+
+func TestParseCardinalityErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test missing bound", "atmost(a, b)", "1:8: unexpected variable 'a', expected an integer"},
+		{"test missing comma", "atmost(1 a)", "1:10: missing closing parenthesis of atmost, found variable 'a', expected ',' or ')'"},
+		{"test missing bound of a sum", "a + b >= c", "1:10: unexpected variable 'c', expected an integer"},
+		{"test constant as a bound", "a + b >= ⊤", "1:10: unexpected number '1', expected an integer"},
+		{"test constant as a bound of a cardinality", "atleast(true, a, b)", "1:9: unexpected number '1', expected an integer"},
+		{"test constant as a coefficient", "⊤a + b >= 1", "1:2: unexpected variable 'a' after a number, expected AND operator, OR operator, XOR operator, '->', '<->', '==' or '!='"},
+		{"test number outside of a constraint", "a & 2", "1:5: the number 2 is not a boolean value, only 0 and 1 are"},
+		{"test comparison without sum", "(a | b) >= 1", "1:9: unexpected '>=' after the expression, expected AND operator, OR operator, XOR operator, '->', '<->', '==', '!=' or end of input"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseInput(test.input)
+			assert.NotNil(err)
+			if err != nil {
+				assert.Equal(test.expected, err.Error(), test.name)
+			}
+		})
+	}
+}
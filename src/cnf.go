@@ -0,0 +1,580 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Maximal number of clauses built by the distributive CNF conversion
+const MAX_CNF_CLAUSES = 10000
+
+/*
+Literal of a clause. The variable numbered k (starting at 1, as in DIMACS) appears
+as the literal k, and its negation as the literal -k
+*/
+type Literal int
+
+/*
+Return the number of the variable of the literal
+*/
+func (literal Literal) Variable() int {
+	if literal < 0 {
+		return int(-literal)
+	}
+	return int(literal)
+}
+
+/*
+Return true if the literal is the negation of its variable
+*/
+func (literal Literal) IsNegated() bool {
+	return literal < 0
+}
+
+/*
+Return the opposite literal
+*/
+func (literal Literal) Not() Literal {
+	return -literal
+}
+
+// Disjunction of literals
+type Clause []Literal
+
+/*
+Conjunction of clauses over named variables. The variable at index k of Variables
+is numbered k+1 in the clauses. The first NbrInputs variables are the variables of
+the converted expression, the next ones are auxiliary variables added by an encoding
+*/
+type CNF struct {
+	Variables []string
+	Clauses   []Clause
+	NbrInputs int
+	indexes   map[string]int
+}
+
+/*
+Create an empty CNF (always true) over the given variables
+*/
+func NewCNF(variables []string) *CNF {
+	cnf := &CNF{Clauses: []Clause{}, indexes: make(map[string]int)}
+	for _, variable := range variables {
+		cnf.variable(variable)
+	}
+	cnf.NbrInputs = len(cnf.Variables)
+	return cnf
+}
+
+/*
+Return the positive literal of a variable, adding the variable if it does not exist yet
+*/
+func (cnf *CNF) Literal(variable string) Literal {
+	return Literal(cnf.variable(variable))
+}
+
+func (cnf *CNF) variable(name string) int {
+	if number, ok := cnf.indexes[name]; ok {
+		return number
+	}
+
+	cnf.Variables = append(cnf.Variables, name)
+	cnf.indexes[name] = len(cnf.Variables)
+	return len(cnf.Variables)
+}
+
+/*
+Add an auxiliary variable with a name not used by the other variables, and return
+its positive literal
+*/
+func (cnf *CNF) NewAuxiliary() Literal {
+	for index := len(cnf.Variables) - cnf.NbrInputs + 1; ; index++ {
+		name := fmt.Sprintf("_t%d", index)
+		if _, ok := cnf.indexes[name]; !ok {
+			return cnf.Literal(name)
+		}
+	}
+}
+
+/*
+Add a clause to the CNF
+*/
+func (cnf *CNF) AddClause(literals ...Literal) {
+	cnf.Clauses = append(cnf.Clauses, append(Clause{}, literals...))
+}
+
+/*
+Return the name of the variable of a literal
+*/
+func (cnf *CNF) Name(literal Literal) string {
+	return cnf.Variables[literal.Variable()-1]
+}
+
+/*
+Return true if every clause has a true literal. Missing variables are false
+*/
+func (cnf *CNF) Eval(variables map[string]bool) bool {
+	for _, clause := range cnf.Clauses {
+		satisfied := false
+		for _, literal := range clause {
+			if variables[cnf.Name(literal)] != literal.IsNegated() {
+				satisfied = true
+				break
+			}
+		}
+
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return the CNF as a conjunction of disjunctions, 1 when it has no clause
+*/
+func (cnf *CNF) ToExpression() Expression {
+	var expr Expression
+	for _, clause := range cnf.Clauses {
+		var disjunction Expression
+		for _, literal := range clause {
+			var term Expression = NewVarExpression(cnf.Name(literal))
+			if literal.IsNegated() {
+				term = NewNotExpression(term)
+			}
+
+			if disjunction == nil {
+				disjunction = term
+			} else {
+				disjunction = NewOrExpression(disjunction, term)
+			}
+		}
+
+		if disjunction == nil {
+			disjunction = NewNumberExpression(0)
+		}
+
+		if expr == nil {
+			expr = disjunction
+		} else {
+			expr = NewAndExpression(expr, disjunction)
+		}
+	}
+
+	if expr == nil {
+		return NewNumberExpression(1)
+	}
+	return expr
+}
+
+/*
+Return the clauses in the ASCII dialect, one clause per parenthesis
+*/
+func (cnf *CNF) String() string {
+	clauses := make([]string, len(cnf.Clauses))
+	for index, clause := range cnf.Clauses {
+		literals := make([]string, len(clause))
+		for position, literal := range clause {
+			literals[position] = cnf.Name(literal)
+			if literal.IsNegated() {
+				literals[position] = "!" + literals[position]
+			}
+		}
+		clauses[index] = fmt.Sprintf("(%s)", strings.Join(literals, " | "))
+	}
+	return strings.Join(clauses, " & ")
+}
+
+/*
+Convert an expression into an equivalent CNF by distributing the disjunctions over the
+conjunctions. The number of clauses can grow exponentially, so an error is returned
+above MAX_CNF_CLAUSES clauses: use TseitinCNF for large expressions
+*/
+func ToCNF(expr Expression) (*CNF, error) {
+	cnf := NewCNF(Variables(expr))
+	clauses, err := distributiveClauses(cnf, expr, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cnf.Clauses = clauses
+	return cnf, nil
+}
+
+/*
+Compute the clauses of the expression, or of its negation. Implications, XOR,
+equivalences and linear constraints are rewritten with AND, OR and NOT
+*/
+func distributiveClauses(cnf *CNF, expr Expression, negated bool) ([]Clause, error) {
+	switch value := expr.(type) {
+	case *VarExpression:
+		literal := cnf.Literal(value.variable)
+		if negated {
+			literal = literal.Not()
+		}
+		return []Clause{{literal}}, nil
+	case *NumberExpression:
+		if (value.value == 1) != negated {
+			return []Clause{}, nil
+		}
+		return []Clause{{}}, nil
+	case *NotExpression:
+		return distributiveClauses(cnf, value.expr, !negated)
+	case *AndExpression:
+		return distributiveJunction(cnf, value.left, value.right, negated, negated, negated)
+	case *OrExpression:
+		return distributiveJunction(cnf, value.left, value.right, negated, negated, !negated)
+	case *ImpliesExpression:
+		return distributiveJunction(cnf, value.left, value.right, !negated, negated, !negated)
+	case *CardinalityExpression, *PseudoBooleanExpression:
+		return distributiveClauses(cnf, expandLinearConstraint(expr), negated)
+	}
+
+	// a + b = (a | b) & (!a | !b), a <-> b = (!a | b) & (a | !b)
+	children := operands(expr)
+	_, isXOR := expr.(*XORExpression)
+	if isXOR == negated {
+		first, err := distributiveJunction(cnf, children[0], children[1], true, false, true)
+		if err != nil {
+			return nil, err
+		}
+		second, err := distributiveJunction(cnf, children[0], children[1], false, true, true)
+		if err != nil {
+			return nil, err
+		}
+		return simplifyClauses(append(first, second...))
+	}
+
+	first, err := distributiveJunction(cnf, children[0], children[1], false, false, true)
+	if err != nil {
+		return nil, err
+	}
+	second, err := distributiveJunction(cnf, children[0], children[1], true, true, true)
+	if err != nil {
+		return nil, err
+	}
+	return simplifyClauses(append(first, second...))
+}
+
+/*
+Compute the clauses of left OR right (disjunction) or left AND right, each operand
+being negated or not
+*/
+func distributiveJunction(cnf *CNF, left, right Expression, negateLeft, negateRight, disjunction bool) ([]Clause, error) {
+	leftClauses, err := distributiveClauses(cnf, left, negateLeft)
+	if err != nil {
+		return nil, err
+	}
+	rightClauses, err := distributiveClauses(cnf, right, negateRight)
+	if err != nil {
+		return nil, err
+	}
+
+	if !disjunction {
+		return simplifyClauses(append(leftClauses, rightClauses...))
+	}
+
+	// (a & b) | (c & d) = (a | c) & (a | d) & (b | c) & (b | d)
+	if len(leftClauses)*len(rightClauses) > MAX_CNF_CLAUSES {
+		return nil, fmt.Errorf("the CNF has more than %d clauses, use the Tseitin encoding instead", MAX_CNF_CLAUSES)
+	}
+
+	clauses := []Clause{}
+	for _, leftClause := range leftClauses {
+		for _, rightClause := range rightClauses {
+			clauses = append(clauses, append(append(Clause{}, leftClause...), rightClause...))
+		}
+	}
+	return simplifyClauses(clauses)
+}
+
+/*
+Remove the duplicated literals, the clauses that are always true and the clauses
+containing another clause
+*/
+func simplifyClauses(clauses []Clause) ([]Clause, error) {
+	normalized := []Clause{}
+	for _, clause := range clauses {
+		if clause, ok := normalizeClause(clause); ok {
+			normalized = append(normalized, clause)
+		}
+	}
+
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return len(normalized[i]) < len(normalized[j])
+	})
+
+	kept := []Clause{}
+	for _, clause := range normalized {
+		subsumed := false
+		for _, other := range kept {
+			if isSubClause(other, clause) {
+				subsumed = true
+				break
+			}
+		}
+
+		if !subsumed {
+			kept = append(kept, clause)
+		}
+	}
+
+	if len(kept) > MAX_CNF_CLAUSES {
+		return nil, fmt.Errorf("the CNF has more than %d clauses, use the Tseitin encoding instead", MAX_CNF_CLAUSES)
+	}
+	return kept, nil
+}
+
+/*
+Sort the literals of a clause by variable and remove the duplicates. The boolean
+is false when the clause contains a literal and its negation
+*/
+func normalizeClause(clause Clause) (Clause, bool) {
+	sorted := append(Clause{}, clause...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Variable() != sorted[j].Variable() {
+			return sorted[i].Variable() < sorted[j].Variable()
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	normalized := Clause{}
+	for index, literal := range sorted {
+		if index > 0 && literal == sorted[index-1] {
+			continue
+		}
+		if index > 0 && literal == sorted[index-1].Not() {
+			return nil, false
+		}
+		normalized = append(normalized, literal)
+	}
+	return normalized, true
+}
+
+/*
+Return true if every literal of the first sorted clause belongs to the second sorted clause
+*/
+func isSubClause(first, second Clause) bool {
+	position := 0
+	for _, literal := range first {
+		for position < len(second) && second[position] != literal {
+			position++
+		}
+		if position == len(second) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Convert an expression into an equisatisfiable CNF with the Tseitin encoding: each
+operator gets an auxiliary variable equivalent to its value, so that the size of the
+CNF is linear in the size of the expression. The values of the auxiliary variables
+are determined by the values of the variables of the expression
+*/
+func TseitinCNF(expr Expression) *CNF {
+	encoder := newTseitinEncoder(Variables(expr), false)
+	return encoder.encode(expr)
+}
+
+/*
+Convert an expression into an equisatisfiable CNF with the Plaisted-Greenbaum encoding:
+like the Tseitin encoding, but each auxiliary variable only implies the value of its
+operator (or is implied by it) depending on the polarity of the operator, which gives
+about half of the clauses
+*/
+func PlaistedGreenbaumCNF(expr Expression) *CNF {
+	encoder := newTseitinEncoder(Variables(expr), true)
+	return encoder.encode(expr)
+}
+
+// Polarities of a sub-expression: positive when it appears under an even number of negations
+const (
+	positivePolarity = 1 << iota
+	negativePolarity
+	bothPolarities = positivePolarity | negativePolarity
+)
+
+/*
+Options of the Tseitin encoding
+*/
+type CNFOptions struct {
+	PolarityAware bool                // Plaisted-Greenbaum encoding instead of Tseitin
+	Cardinality   CardinalityEncoding // Encoding of the cardinality and pseudo-Boolean constraints
+}
+
+/*
+Convert an expression into an equisatisfiable CNF with the Tseitin (or Plaisted-Greenbaum)
+encoding, the cardinality and pseudo-Boolean constraints being encoded as chosen
+*/
+func TseitinCNFWithOptions(expr Expression, options CNFOptions) *CNF {
+	encoder := newTseitinEncoder(Variables(expr), options.PolarityAware)
+	encoder.cardinality = options.Cardinality
+	return encoder.encode(expr)
+}
+
+type tseitinEncoder struct {
+	cnf           *CNF
+	polarityAware bool
+	cardinality   CardinalityEncoding
+	literals      map[Expression]Literal
+	encoded       map[Expression]int        // Polarities already encoded for each operator
+	counters      map[string]counterOutputs // Outputs of the counters of the linear constraints
+	trueLiteral   Literal                   // Literal always true, 0 until it is used
+}
+
+func newTseitinEncoder(variables []string, polarityAware bool) *tseitinEncoder {
+	return &tseitinEncoder{
+		cnf:           NewCNF(variables),
+		polarityAware: polarityAware,
+		literals:      make(map[Expression]Literal),
+		encoded:       make(map[Expression]int),
+		counters:      make(map[string]counterOutputs),
+	}
+}
+
+func (encoder *tseitinEncoder) encode(expr Expression) *CNF {
+	encoder.cnf.AddClause(encoder.literal(expr, positivePolarity))
+	return encoder.cnf
+}
+
+/*
+Return the literal equivalent to the expression, adding the clauses defining it for
+the given polarities
+*/
+func (encoder *tseitinEncoder) literal(expr Expression, polarity int) Literal {
+	if !encoder.polarityAware {
+		polarity = bothPolarities
+	}
+
+	switch value := expr.(type) {
+	case *VarExpression:
+		return encoder.cnf.Literal(value.variable)
+	case *NotExpression:
+		return encoder.literal(value.expr, flipPolarity(polarity)).Not()
+	}
+
+	literal, ok := encoder.literals[expr]
+	if !ok {
+		literal = encoder.cnf.NewAuxiliary()
+		encoder.literals[expr] = literal
+	}
+
+	missing := polarity &^ encoder.encoded[expr]
+	if missing == 0 {
+		return literal
+	}
+	encoder.encoded[expr] |= missing
+
+	switch value := expr.(type) {
+	case *NumberExpression:
+		if value.value == 1 {
+			encoder.cnf.AddClause(literal)
+		} else {
+			encoder.cnf.AddClause(literal.Not())
+		}
+	case *AndExpression, *OrExpression:
+		encoder.encodeJunction(expr, literal, missing)
+	case *CardinalityExpression, *PseudoBooleanExpression:
+		encoder.encodeLinear(expr, literal, missing)
+	case *ImpliesExpression:
+		// t <-> (!a | b)
+		left := encoder.literal(value.left, flipPolarity(missing)).Not()
+		right := encoder.literal(value.right, missing)
+		encoder.addJunctionClauses(literal, []Literal{left, right}, false, missing)
+	default:
+		children := operands(expr)
+		left := encoder.literal(children[0], bothPolarities)
+		right := encoder.literal(children[1], bothPolarities)
+		if _, isXOR := expr.(*XORExpression); isXOR {
+			right = right.Not()
+		}
+
+		// t <-> (a <-> b)
+		if missing&positivePolarity != 0 {
+			encoder.cnf.AddClause(literal.Not(), left.Not(), right)
+			encoder.cnf.AddClause(literal.Not(), left, right.Not())
+		}
+		if missing&negativePolarity != 0 {
+			encoder.cnf.AddClause(literal, left, right)
+			encoder.cnf.AddClause(literal, left.Not(), right.Not())
+		}
+	}
+
+	return literal
+}
+
+/*
+Encode a chain of AND (or OR) operators with a single auxiliary variable
+*/
+func (encoder *tseitinEncoder) encodeJunction(expr Expression, literal Literal, polarity int) {
+	_, isAnd := expr.(*AndExpression)
+	operands := []Literal{}
+	for _, operand := range junctionOperands(expr, isAnd) {
+		operands = append(operands, encoder.literal(operand, polarity))
+	}
+	encoder.addJunctionClauses(literal, operands, isAnd, polarity)
+}
+
+/*
+Add the clauses of t <-> (a1 & ... & an), or t <-> (a1 | ... | an)
+*/
+func (encoder *tseitinEncoder) addJunctionClauses(literal Literal, operands []Literal, isAnd bool, polarity int) {
+	if isAnd {
+		// t -> ai, (a1 & ... & an) -> t
+		if polarity&positivePolarity != 0 {
+			for _, operand := range operands {
+				encoder.cnf.AddClause(literal.Not(), operand)
+			}
+		}
+		if polarity&negativePolarity != 0 {
+			clause := Clause{literal}
+			for _, operand := range operands {
+				clause = append(clause, operand.Not())
+			}
+			encoder.cnf.AddClause(clause...)
+		}
+		return
+	}
+
+	// t -> (a1 | ... | an), ai -> t
+	if polarity&positivePolarity != 0 {
+		clause := Clause{literal.Not()}
+		clause = append(clause, operands...)
+		encoder.cnf.AddClause(clause...)
+	}
+	if polarity&negativePolarity != 0 {
+		for _, operand := range operands {
+			encoder.cnf.AddClause(literal, operand.Not())
+		}
+	}
+}
+
+/*
+Return the operands of a chain of AND (or OR) operators
+*/
+func junctionOperands(expr Expression, isAnd bool) []Expression {
+	switch value := expr.(type) {
+	case *AndExpression:
+		if isAnd {
+			return append(junctionOperands(value.left, isAnd), junctionOperands(value.right, isAnd)...)
+		}
+	case *OrExpression:
+		if !isAnd {
+			return append(junctionOperands(value.left, isAnd), junctionOperands(value.right, isAnd)...)
+		}
+	}
+	return []Expression{expr}
+}
+
+func flipPolarity(polarity int) int {
+	flipped := 0
+	if polarity&positivePolarity != 0 {
+		flipped |= negativePolarity
+	}
+	if polarity&negativePolarity != 0 {
+		flipped |= positivePolarity
+	}
+	return flipped
+}
@@ -0,0 +1,159 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return, for each assignment of the variables of the expression, the number of values of
+the auxiliary variables satisfying the CNF
+*/
+func auxiliaryModels(cnf *CNF) []int {
+	inputs := cnf.Variables[:cnf.NbrInputs]
+	auxiliaries := cnf.Variables[cnf.NbrInputs:]
+
+	models := make([]int, 1<<len(inputs))
+	for row := range models {
+		assignment := assignmentOf(row, inputs)
+		for auxiliaryRow := 0; auxiliaryRow < 1<<len(auxiliaries); auxiliaryRow++ {
+			for name, value := range assignmentOf(auxiliaryRow, auxiliaries) {
+				assignment[name] = value
+			}
+			if cnf.Eval(assignment) {
+				models[row]++
+			}
+		}
+	}
+	return models
+}
+
+func TestToCNF(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test and", "a & b", "(a) & (b)"},
+		{"test distribution", "a | (b & c)", "(a | b) & (a | c)"},
+		{"test implies", "a -> b", "(!a | b)"},
+		{"test xor", "a + b", "(a | b) & (!a | !b)"},
+		{"test equivalence", "a <-> b", "(!a | b) & (a | !b)"},
+		{"test de morgan", "!(a | b)", "(!a) & (!b)"},
+		{"test tautology", "a | !a", ""},
+		{"test contradiction", "a & !a", "(a) & (!a)"},
+		{"test subsumption", "(a | b) & a", "(a)"},
+		{"test constant", "1", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			cnf, err := ToCNF(expr)
+			assert.Nil(err)
+			assert.Equal(test.expected, cnf.String(), test.name)
+			assert.True(sameTruthTable(expr, cnf.ToExpression()), test.name)
+		})
+	}
+}
+
+func TestToCNFRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(8))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 5, variables)
+		cnf, err := ToCNF(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, cnf.ToExpression()), Print(expr, ASCII))
+		assert.Equal(len(cnf.Variables), cnf.NbrInputs)
+	}
+}
+
+func TestToCNFTooLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	// (a0 & b0) | (a1 & b1) | ... has 2^n clauses
+	expr, _ := parseInput("(a0 & b0) | (a1 & b1) | (a2 & b2) | (a3 & b3) | (a4 & b4) | (a5 & b5) | (a6 & b6) | (a7 & b7) | (a8 & b8) | (a9 & b9) | (a10 & b10) | (a11 & b11) | (a12 & b12) | (a13 & b13) | (a14 & b14)")
+	_, err := ToCNF(expr)
+	assert.NotNil(err)
+
+	cnf := TseitinCNF(expr)
+	assert.Less(len(cnf.Clauses), 100)
+}
+
+func TestTseitinCNF(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(9))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 3, variables)
+		cnf := TseitinCNF(expr)
+		inputs := cnf.Variables[:cnf.NbrInputs]
+
+		// the auxiliary variables are determined by the inputs
+		for row, models := range auxiliaryModels(cnf) {
+			expected := 0
+			if expr.Eval(assignmentOf(row, inputs)) {
+				expected = 1
+			}
+			assert.Equal(expected, models, Print(expr, ASCII))
+		}
+	}
+}
+
+func TestPlaistedGreenbaumCNF(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(10))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 3, variables)
+		cnf := PlaistedGreenbaumCNF(expr)
+		inputs := cnf.Variables[:cnf.NbrInputs]
+
+		for row, models := range auxiliaryModels(cnf) {
+			assert.Equal(expr.Eval(assignmentOf(row, inputs)), models > 0, Print(expr, ASCII))
+		}
+		assert.LessOrEqual(len(cnf.Clauses), len(TseitinCNF(expr).Clauses))
+	}
+}
+
+func TestTseitinCNFFlattensJunctions(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a & b & c & d")
+	cnf := TseitinCNF(expr)
+	assert.Equal([]string{"a", "b", "c", "d", "_t1"}, cnf.Variables)
+	assert.Equal("(_t1) & (!_t1 | a) & (!_t1 | b) & (!_t1 | c) & (!_t1 | d) & (_t1 | !a | !b | !c | !d)", rootClauseFirst(cnf))
+
+	expr, _ = parseInput("!(a | b)")
+	cnf = PlaistedGreenbaumCNF(expr)
+	assert.Equal("(!_t1) & (_t1 | !a) & (_t1 | !b)", rootClauseFirst(cnf))
+}
+
+func TestCNFAuxiliaryNames(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("_t1 & b")
+	cnf := TseitinCNF(expr)
+	assert.Equal([]string{"_t1", "b", "_t2"}, cnf.Variables)
+	assert.Equal(2, cnf.NbrInputs)
+}
+
+/*
+Print the clauses with the root clause, added last by the encoders, first
+*/
+func rootClauseFirst(cnf *CNF) string {
+	sorted := NewCNF(cnf.Variables)
+	sorted.Clauses = append([]Clause{cnf.Clauses[len(cnf.Clauses)-1]}, cnf.Clauses[:len(cnf.Clauses)-1]...)
+	return sorted.String()
+}
@@ -0,0 +1,134 @@
+package logic
+
+/*
+Expression identified by a name, such as a rule of a configuration
+*/
+type Constraint struct {
+	Name string
+	Expr Expression
+}
+
+/*
+Encode the constraints into a CNF where each constraint is only enforced when its
+selector literal is true, so that the solver can enable any subset of them through
+its assumptions
+*/
+func selectorCNF(constraints []Constraint) (*CNF, []Literal) {
+	exprs := make([]Expression, len(constraints))
+	for index, constraint := range constraints {
+		exprs[index] = constraint.Expr
+	}
+
+	encoder := newTseitinEncoder(variablesOf(exprs...), true)
+	selectors := make([]Literal, len(constraints))
+	for index, expr := range exprs {
+		literal := encoder.literal(expr, positivePolarity)
+		selectors[index] = encoder.cnf.NewAuxiliary()
+		encoder.cnf.AddClause(selectors[index].Not(), literal)
+	}
+	return encoder.cnf, selectors
+}
+
+/*
+Solver of a set of constraints that can be enabled separately
+*/
+type constraintSolver struct {
+	solver    *SATSolver
+	selectors []Literal
+	indexes   map[Literal]int // Constraint of each selector
+}
+
+func newConstraintSolver(constraints []Constraint) *constraintSolver {
+	cnf, selectors := selectorCNF(constraints)
+	indexes := make(map[Literal]int, len(selectors))
+	for index, selector := range selectors {
+		indexes[selector] = index
+	}
+	return &constraintSolver{solver: NewSATSolverFromCNF(cnf), selectors: selectors, indexes: indexes}
+}
+
+/*
+Decide whether the constraints of the given indexes are satisfiable together. When they
+are not, return the indexes of the constraints involved in the conflict, in the order
+of the subset
+*/
+func (solver *constraintSolver) solve(subset []int) (bool, []int) {
+	assumptions := make([]Literal, len(subset))
+	for index, constraint := range subset {
+		assumptions[index] = solver.selectors[constraint]
+	}
+	if solver.solver.Solve(assumptions...) {
+		return true, nil
+	}
+
+	failed := make(map[int]bool)
+	for _, literal := range solver.solver.FailedAssumptions() {
+		failed[solver.indexes[literal]] = true
+	}
+	core := []int{}
+	for _, constraint := range subset {
+		if failed[constraint] {
+			core = append(core, constraint)
+		}
+	}
+	return false, core
+}
+
+/*
+Return the names of a subset of the constraints that is unsatisfiable, found by the
+SAT solver from the constraints used in its refutation. The subset is not always
+minimal, see MinimalUnsatisfiableSubset. Return false when the constraints are
+satisfiable together
+*/
+func UnsatisfiableCore(constraints []Constraint) ([]string, bool) {
+	solver := newConstraintSolver(constraints)
+	satisfiable, core := solver.solve(allIndexes(len(constraints)))
+	if satisfiable {
+		return nil, false
+	}
+	return constraintNames(constraints, core), true
+}
+
+/*
+Return the names of a minimal unsatisfiable subset of the constraints (MUS): the
+constraints of the subset are contradictory, but removing any one of them makes the
+others satisfiable. Return false when the constraints are satisfiable together.
+
+The subset is found by deletion: starting from an unsatisfiable core, each constraint
+is removed in turn, and put back if the others become satisfiable. When they are still
+unsatisfiable, the core is reduced to the constraints of the new refutation
+*/
+func MinimalUnsatisfiableSubset(constraints []Constraint) ([]string, bool) {
+	solver := newConstraintSolver(constraints)
+	satisfiable, core := solver.solve(allIndexes(len(constraints)))
+	if satisfiable {
+		return nil, false
+	}
+
+	// the constraints before position are necessary, so they belong to every refutation
+	for position := 0; position < len(core); {
+		candidate := append(append([]int{}, core[:position]...), core[position+1:]...)
+		if satisfiable, reduced := solver.solve(candidate); satisfiable {
+			position++
+		} else {
+			core = reduced
+		}
+	}
+	return constraintNames(constraints, core), true
+}
+
+func allIndexes(size int) []int {
+	indexes := make([]int, size)
+	for index := range indexes {
+		indexes[index] = index
+	}
+	return indexes
+}
+
+func constraintNames(constraints []Constraint, indexes []int) []string {
+	names := make([]string, len(indexes))
+	for index, constraint := range indexes {
+		names[index] = constraints[constraint].Name
+	}
+	return names
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseConstraints(inputs map[string]string, names []string) []Constraint {
+	constraints := []Constraint{}
+	for _, name := range names {
+		expr, _ := parseInput(inputs[name])
+		constraints = append(constraints, Constraint{Name: name, Expr: expr})
+	}
+	return constraints
+}
+
+/*
+Return whether the named constraints are satisfiable together
+*/
+func satisfiableTogether(constraints []Constraint, names []string) bool {
+	var expr Expression = NewNumberExpression(1)
+	for _, constraint := range constraints {
+		for _, name := range names {
+			if constraint.Name == name {
+				expr = NewAndExpression(expr, constraint.Expr)
+			}
+		}
+	}
+	satisfiable, _ := IsSatisfiable(expr)
+	return satisfiable
+}
+
+/*
+Assert that the subset is unsatisfiable, and satisfiable without any of its constraints
+*/
+func assertMinimalUnsatisfiable(assert *assert.Assertions, constraints []Constraint, subset []string) {
+	assert.False(satisfiableTogether(constraints, subset))
+	for index := range subset {
+		others := append(append([]string{}, subset[:index]...), subset[index+1:]...)
+		assert.True(satisfiableTogether(constraints, others), "%v is not minimal", subset)
+	}
+}
+
+func TestMinimalUnsatisfiableSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	inputs := map[string]string{
+		"needs_ssl":     "https -> ssl",
+		"https":         "https",
+		"no_ssl":        "!ssl",
+		"cache":         "cache | cdn",
+		"legacy":        "legacy -> !https",
+		"legacy_on":     "legacy",
+		"cdn_needs_ssl": "cdn -> ssl",
+		"contradiction": "debug & !debug",
+	}
+
+	tests := []struct {
+		name        string
+		constraints []string
+		expected    []string
+	}{
+		{"test ssl", []string{"cache", "needs_ssl", "https", "no_ssl"}, []string{"needs_ssl", "https", "no_ssl"}},
+		{"test legacy", []string{"legacy", "needs_ssl", "https", "legacy_on", "cache"}, []string{"legacy", "https", "legacy_on"}},
+		{"test single constraint", []string{"https", "contradiction", "cache"}, []string{"contradiction"}},
+		{"test satisfiable", []string{"cache", "cdn_needs_ssl", "https", "needs_ssl"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			constraints := parseConstraints(inputs, test.constraints)
+			mus, unsatisfiable := MinimalUnsatisfiableSubset(constraints)
+			assert.Equal(test.expected != nil, unsatisfiable)
+			assert.Equal(test.expected, mus)
+
+			core, unsatisfiable := UnsatisfiableCore(constraints)
+			assert.Equal(test.expected != nil, unsatisfiable)
+			if unsatisfiable {
+				assert.False(satisfiableTogether(constraints, core))
+				assert.Subset(core, mus)
+			}
+		})
+	}
+}
+
+func TestMinimalUnsatisfiableSubsetRandomConstraints(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(26))
+	variables := []string{"a", "b", "c", "d"}
+
+	unsatisfiableSets := 0
+	for i := 0; i < 200; i++ {
+		constraints := []Constraint{}
+		for index := 0; index < 8; index++ {
+			constraints = append(constraints, Constraint{Name: fmt.Sprintf("c%d", index), Expr: randomExpression(random, 2, variables)})
+		}
+
+		mus, unsatisfiable := MinimalUnsatisfiableSubset(constraints)
+		assert.Equal(!satisfiableTogether(constraints, constraintNames(constraints, allIndexes(8))), unsatisfiable)
+		if unsatisfiable {
+			unsatisfiableSets++
+			assertMinimalUnsatisfiable(assert, constraints, mus)
+		}
+	}
+	assert.Greater(unsatisfiableSets, 20)
+}
+
+func TestMinimalUnsatisfiableSubsetPigeonhole(t *testing.T) {
+	assert := assert.New(t)
+
+	// 6 pigeons can not be in 3 holes, but a part of the rules is enough for a contradiction
+	cnf := pigeonholeCNF(6, 3)
+	constraints := []Constraint{}
+	for index, clause := range cnf.Clauses {
+		expr := (&CNF{Variables: cnf.Variables, Clauses: []Clause{clause}}).ToExpression()
+		constraints = append(constraints, Constraint{Name: fmt.Sprintf("clause%d", index), Expr: expr})
+	}
+
+	mus, unsatisfiable := MinimalUnsatisfiableSubset(constraints)
+	assert.True(unsatisfiable)
+	assertMinimalUnsatisfiable(assert, constraints, mus)
+	assert.Less(len(mus), len(constraints))
+}
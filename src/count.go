@@ -0,0 +1,300 @@
+package logic
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Count the assignments of the variables of the expression for which it is true, without
+building the truth table: the Tseitin encoding of the expression is counted by a DPLL
+search splitting the clauses into independent components and caching their counts.
+The auxiliary variables of the encoding are determined by the variables of the
+expression, so they do not change the count
+*/
+func CountModels(expr Expression) *big.Int {
+	return TseitinCNF(expr).CountModels()
+}
+
+/*
+Count the assignments of all the variables of the CNF satisfying its clauses
+*/
+func (cnf *CNF) CountModels() *big.Int {
+	counter := newModelCounter(modelCountSemiring[*big.Int]{
+		zero:     func() *big.Int { return big.NewInt(0) },
+		one:      func() *big.Int { return big.NewInt(1) },
+		add:      func(first, second *big.Int) *big.Int { return new(big.Int).Add(first, second) },
+		multiply: func(first, second *big.Int) *big.Int { return new(big.Int).Mul(first, second) },
+		weight:   func(literal Literal) *big.Int { return big.NewInt(1) },
+	})
+	return counter.count(cnf)
+}
+
+/*
+Return the probability that the expression is true when each variable is true with the
+given probability, independently of the others. The variables without probability are
+true with the probability 0.5, so that the result is the fraction of the rows of the
+truth table where the expression is true
+*/
+func Probability(expr Expression, probabilities map[string]float64) (float64, error) {
+	for variable, probability := range probabilities {
+		if !(probability >= 0 && probability <= 1) {
+			return 0, fmt.Errorf("the probability of %s must be between 0 and 1, found %g", variable, probability)
+		}
+	}
+
+	cnf := TseitinCNF(expr)
+	counter := newModelCounter(modelCountSemiring[float64]{
+		zero:     func() float64 { return 0 },
+		one:      func() float64 { return 1 },
+		add:      func(first, second float64) float64 { return first + second },
+		multiply: func(first, second float64) float64 { return first * second },
+		weight: func(literal Literal) float64 {
+			// the auxiliary variables have a single value, given by the other variables
+			if literal.Variable() > cnf.NbrInputs {
+				return 1
+			}
+
+			probability, ok := probabilities[cnf.Name(literal)]
+			if !ok {
+				probability = 0.5
+			}
+			if literal.IsNegated() {
+				return 1 - probability
+			}
+			return probability
+		},
+	})
+	return counter.count(cnf), nil
+}
+
+/*
+Parse probabilities written as "a=0.3, b=0.9"
+*/
+func ParseProbabilities(list string) (map[string]float64, error) {
+	probabilities := make(map[string]float64)
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		variable, value, found := strings.Cut(item, "=")
+		probability, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !found || err != nil || strings.TrimSpace(variable) == "" {
+			return nil, fmt.Errorf("invalid probability '%s', expected <variable>=<probability>", strings.TrimSpace(item))
+		}
+		probabilities[strings.TrimSpace(variable)] = probability
+	}
+	return probabilities, nil
+}
+
+/*
+Operations used to count the models: the count of a model is the product of the
+weights of its literals, and the count of a formula is the sum of its models
+*/
+type modelCountSemiring[T any] struct {
+	zero, one     func() T
+	add, multiply func(T, T) T
+	weight        func(literal Literal) T
+}
+
+type modelCounter[T any] struct {
+	semiring modelCountSemiring[T]
+	cache    map[string]T
+}
+
+func newModelCounter[T any](semiring modelCountSemiring[T]) *modelCounter[T] {
+	return &modelCounter[T]{semiring: semiring, cache: make(map[string]T)}
+}
+
+func (counter *modelCounter[T]) count(cnf *CNF) T {
+	variables := []int{}
+	for variable := 1; variable <= len(cnf.Variables); variable++ {
+		variables = append(variables, variable)
+	}
+
+	clauses := []Clause{}
+	for _, clause := range cnf.Clauses {
+		normalized, ok := normalizeClause(clause)
+		if ok {
+			clauses = append(clauses, normalized)
+		}
+	}
+	return counter.countClauses(clauses, variables)
+}
+
+/*
+Count the models of the clauses over the variables, some of them not appearing in the clauses
+*/
+func (counter *modelCounter[T]) countClauses(clauses []Clause, variables []int) T {
+	semiring := counter.semiring
+	result := semiring.one()
+
+	// unit propagation
+	assigned := make(map[int]bool)
+	for {
+		unit := Literal(0)
+		for _, clause := range clauses {
+			if len(clause) == 0 {
+				return semiring.zero()
+			}
+			if len(clause) == 1 {
+				unit = clause[0]
+				break
+			}
+		}
+
+		if unit == 0 {
+			break
+		}
+		assigned[unit.Variable()] = true
+		result = semiring.multiply(result, semiring.weight(unit))
+		clauses = assignLiteral(clauses, unit)
+	}
+
+	// the variables that no longer appear take both values
+	occurring := make(map[int]bool)
+	for _, clause := range clauses {
+		for _, literal := range clause {
+			occurring[literal.Variable()] = true
+		}
+	}
+	for _, variable := range variables {
+		if !assigned[variable] && !occurring[variable] {
+			free := semiring.add(semiring.weight(Literal(variable)), semiring.weight(Literal(variable).Not()))
+			result = semiring.multiply(result, free)
+		}
+	}
+
+	for _, component := range clauseComponents(clauses) {
+		result = semiring.multiply(result, counter.countComponent(component))
+	}
+	return result
+}
+
+/*
+Count the models of a set of clauses sharing variables, by branching on its most
+frequent variable
+*/
+func (counter *modelCounter[T]) countComponent(clauses []Clause) T {
+	key := clausesKey(clauses)
+	if count, ok := counter.cache[key]; ok {
+		return count
+	}
+
+	occurrences := make(map[int]int)
+	for _, clause := range clauses {
+		for _, literal := range clause {
+			occurrences[literal.Variable()]++
+		}
+	}
+
+	variables := []int{}
+	branch := 0
+	for variable, occurrence := range occurrences {
+		variables = append(variables, variable)
+		if branch == 0 || occurrence > occurrences[branch] || (occurrence == occurrences[branch] && variable < branch) {
+			branch = variable
+		}
+	}
+
+	semiring := counter.semiring
+	count := semiring.zero()
+	for _, literal := range []Literal{Literal(branch), Literal(branch).Not()} {
+		remaining := []int{}
+		for _, variable := range variables {
+			if variable != branch {
+				remaining = append(remaining, variable)
+			}
+		}
+
+		branchCount := counter.countClauses(assignLiteral(clauses, literal), remaining)
+		count = semiring.add(count, semiring.multiply(semiring.weight(literal), branchCount))
+	}
+
+	counter.cache[key] = count
+	return count
+}
+
+/*
+Return the clauses once the literal is true: the clauses containing it are removed,
+and its negation is removed from the others
+*/
+func assignLiteral(clauses []Clause, literal Literal) []Clause {
+	assigned := []Clause{}
+	for _, clause := range clauses {
+		satisfied := false
+		reduced := Clause{}
+		for _, other := range clause {
+			if other == literal {
+				satisfied = true
+				break
+			}
+			if other != literal.Not() {
+				reduced = append(reduced, other)
+			}
+		}
+
+		if !satisfied {
+			assigned = append(assigned, reduced)
+		}
+	}
+	return assigned
+}
+
+/*
+Split the clauses into groups sharing no variable
+*/
+func clauseComponents(clauses []Clause) [][]Clause {
+	parents := make(map[int]int)
+	var find func(variable int) int
+	find = func(variable int) int {
+		parent, ok := parents[variable]
+		if !ok || parent == variable {
+			parents[variable] = variable
+			return variable
+		}
+		root := find(parent)
+		parents[variable] = root
+		return root
+	}
+
+	for _, clause := range clauses {
+		for _, literal := range clause[1:] {
+			parents[find(literal.Variable())] = find(clause[0].Variable())
+		}
+	}
+
+	components := [][]Clause{}
+	indexes := make(map[int]int)
+	for _, clause := range clauses {
+		root := find(clause[0].Variable())
+		index, ok := indexes[root]
+		if !ok {
+			index = len(components)
+			indexes[root] = index
+			components = append(components, []Clause{})
+		}
+		components[index] = append(components[index], clause)
+	}
+	return components
+}
+
+/*
+Return a key identifying a set of clauses whatever their order
+*/
+func clausesKey(clauses []Clause) string {
+	keys := make([]string, len(clauses))
+	for index, clause := range clauses {
+		literals := make([]string, len(clause))
+		for position, literal := range clause {
+			literals[position] = fmt.Sprint(int(literal))
+		}
+		keys[index] = strings.Join(literals, " ")
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
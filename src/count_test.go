@@ -0,0 +1,179 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountModels(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{"test variable", "a", 1},
+		{"test and", "a & b & c", 1},
+		{"test or", "a | b | c", 7},
+		{"test xor", "a + b + c", 4},
+		{"test contradiction", "a & !a", 0},
+		{"test tautology", "a | !a", 2},
+		{"test constant true", "1", 1},
+		{"test constant false", "0", 0},
+		{"test implies", "a -> b", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+			assert.Equal(big.NewInt(test.expected), CountModels(expr), test.name)
+		})
+	}
+}
+
+func TestCountModelsRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(18))
+	variables := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i := 0; i < 300; i++ {
+		expr := randomExpression(random, 5, variables)
+		expected := int64(len(mintermsOf(expr, Variables(expr))))
+		assert.Equal(big.NewInt(expected), CountModels(expr), Print(expr, ASCII))
+	}
+}
+
+func TestCountModelsRandomThreeSAT(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(19))
+
+	for i := 0; i < 20; i++ {
+		cnf := randomThreeSAT(random, 14, 40, nil)
+		expected := 0
+		for row := 0; row < 1<<len(cnf.Variables); row++ {
+			if cnf.Eval(assignmentOf(row, cnf.Variables)) {
+				expected++
+			}
+		}
+		assert.Equal(big.NewInt(int64(expected)), cnf.CountModels())
+	}
+}
+
+func TestCountModelsManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	// 100 independent implications: 3^100 models among 2^200 rows
+	var expr Expression
+	for index := 0; index < 100; index++ {
+		implication := NewImpliesExpression(NewVarExpression(fmt.Sprintf("a%d", index)), NewVarExpression(fmt.Sprintf("b%d", index)))
+		if expr == nil {
+			expr = implication
+		} else {
+			expr = NewAndExpression(expr, implication)
+		}
+	}
+
+	expected := new(big.Int).Exp(big.NewInt(3), big.NewInt(100), nil)
+	assert.Equal(expected, CountModels(expr))
+
+	// in a chain of implications a0 -> a1 -> ... -> a300, the true variables follow the false ones
+	expr = NewNumberExpression(1)
+	for index := 0; index < 300; index++ {
+		implication := NewImpliesExpression(NewVarExpression(fmt.Sprintf("a%d", index)), NewVarExpression(fmt.Sprintf("a%d", index+1)))
+		expr = NewAndExpression(expr, implication)
+	}
+	assert.Equal(big.NewInt(302), CountModels(expr))
+}
+
+func TestProbability(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name          string
+		input         string
+		probabilities map[string]float64
+		expected      float64
+	}{
+		{"test and", "a & b", map[string]float64{"a": 0.5, "b": 0.2}, 0.1},
+		{"test or", "a | b", map[string]float64{"a": 0.5, "b": 0.2}, 0.6},
+		{"test default probability", "a & b", map[string]float64{"a": 0.4}, 0.2},
+		{"test xor", "a + b", map[string]float64{"a": 0.9, "b": 0.9}, 0.18},
+		{"test certain", "a -> b", map[string]float64{"a": 1, "b": 0}, 0},
+		{"test contradiction", "a & !a", map[string]float64{"a": 0.3}, 0},
+		{"test constant", "1", map[string]float64{}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			probability, err := Probability(expr, test.probabilities)
+			assert.Nil(err)
+			assert.InDelta(test.expected, probability, 1e-9, test.name)
+		})
+	}
+}
+
+func TestProbabilityRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(20))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 5, variables)
+		probabilities := make(map[string]float64)
+		for _, variable := range variables {
+			probabilities[variable] = random.Float64()
+		}
+
+		expected := 0.0
+		for _, row := range mintermsOf(expr, variables) {
+			weight := 1.0
+			for variable, value := range assignmentOf(row, variables) {
+				if value {
+					weight *= probabilities[variable]
+				} else {
+					weight *= 1 - probabilities[variable]
+				}
+			}
+			expected += weight
+		}
+
+		probability, err := Probability(expr, probabilities)
+		assert.Nil(err)
+		assert.InDelta(expected, probability, 1e-9, Print(expr, ASCII))
+	}
+}
+
+func TestProbabilityInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a & b")
+	_, err := Probability(expr, map[string]float64{"a": 1.5})
+	assert.Equal("the probability of a must be between 0 and 1, found 1.5", err.Error())
+
+	_, err = Probability(expr, map[string]float64{"b": math.NaN()})
+	assert.NotNil(err)
+}
+
+func TestParseProbabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	probabilities, err := ParseProbabilities("a=0.3, b = 1,")
+	assert.Nil(err)
+	assert.Equal(map[string]float64{"a": 0.3, "b": 1}, probabilities)
+
+	_, err = ParseProbabilities("a=0.3, b")
+	assert.Equal("invalid probability 'b', expected <variable>=<probability>", err.Error())
+
+	_, err = ParseProbabilities("a=high")
+	assert.NotNil(err)
+}
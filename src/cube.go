@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"math/bits"
+	"sort"
+)
+
+/*
+Product of literals over indexed variables. The variable k appears in the cube
+when the bit k of mask is set, negated if the bit k of value is not set.
+Bits of value outside of mask are always 0
+*/
+type cube struct {
+	value uint64
+	mask  uint64
+}
+
+/*
+Create the cube of a single row of the truth table
+*/
+func mintermCube(minterm int, nbrVariables int) cube {
+	return cube{value: uint64(minterm), mask: fullMask(nbrVariables)}
+}
+
+func fullMask(nbrVariables int) uint64 {
+	if nbrVariables >= 64 {
+		return ^uint64(0)
+	}
+	return (uint64(1) << nbrVariables) - 1
+}
+
+/*
+Return true if the row of the truth table belongs to the cube
+*/
+func (c cube) containsMinterm(minterm uint64) bool {
+	return minterm&c.mask == c.value
+}
+
+/*
+Return true if every row of the other cube belongs to this cube
+*/
+func (c cube) covers(other cube) bool {
+	return other.mask&c.mask == c.mask && other.value&c.mask == c.value
+}
+
+/*
+Return true if the two cubes share at least one row
+*/
+func (c cube) intersects(other cube) bool {
+	common := c.mask & other.mask
+	return c.value&common == other.value&common
+}
+
+/*
+Return the number of literals of the cube
+*/
+func (c cube) literals() int {
+	return bits.OnesCount64(c.mask)
+}
+
+/*
+Return the cube as a conjunction of literals, or 1 when it has no literal
+*/
+func (c cube) toExpression(variables []string) Expression {
+	var expr Expression
+	for index, variable := range variables {
+		bit := uint64(1) << index
+		if c.mask&bit == 0 {
+			continue
+		}
+
+		var literal Expression = NewVarExpression(variable)
+		if c.value&bit == 0 {
+			literal = NewNotExpression(literal)
+		}
+
+		if expr == nil {
+			expr = literal
+		} else {
+			expr = NewAndExpression(expr, literal)
+		}
+	}
+
+	if expr == nil {
+		return NewNumberExpression(1)
+	}
+	return expr
+}
+
+/*
+Return a cover as a disjunction of its cubes, or 0 when it is empty
+*/
+func coverToExpression(cover []cube, variables []string) Expression {
+	sortCubes(cover)
+
+	var expr Expression
+	for _, c := range cover {
+		if expr == nil {
+			expr = c.toExpression(variables)
+		} else {
+			expr = NewOrExpression(expr, c.toExpression(variables))
+		}
+	}
+
+	if expr == nil {
+		return NewNumberExpression(0)
+	}
+	return expr
+}
+
+/*
+Sort cubes so that the literals of the first variables come first, positive before negative
+*/
+func sortCubes(cubes []cube) {
+	sort.Slice(cubes, func(i, j int) bool {
+		a, b := cubes[i], cubes[j]
+		for bit := uint64(1); bit != 0; bit <<= 1 {
+			if a.mask&bit != b.mask&bit {
+				return a.mask&bit != 0
+			}
+			if a.value&bit != b.value&bit {
+				return a.value&bit != 0
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,190 @@
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Prefix of the comment lines giving the name of a variable: "c var <index> <name>"
+const DIMACS_VARIABLE_COMMENT = "var"
+
+/*
+Read a DIMACS CNF problem and return it as an expression, a conjunction of clauses.
+See ReadDIMACSCNF for the names of the variables
+*/
+func ReadDIMACS(reader io.Reader) (Expression, error) {
+	cnf, err := ReadDIMACSCNF(reader)
+	if err != nil {
+		return nil, err
+	}
+	return cnf.ToExpression(), nil
+}
+
+/*
+Read a DIMACS CNF problem. The variables are named by the "c var <index> <name>"
+comment lines, as written by WriteDIMACS. The other variables are named x<index>,
+or _x<index> when this name is already used
+*/
+func ReadDIMACSCNF(reader io.Reader) (*CNF, error) {
+	scanner := bufio.NewScanner(reader)
+	names := make(map[int]string)
+	usedNames := make(map[string]bool)
+	nbrVariables, nbrClauses := -1, 0
+	clauses := []Clause{}
+	clause := Clause{}
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "c":
+			if len(fields) == 4 && fields[1] == DIMACS_VARIABLE_COMMENT {
+				index, err := strconv.Atoi(fields[2])
+				if err != nil || index <= 0 {
+					return nil, fmt.Errorf("line %d: invalid variable index '%s'", line, fields[2])
+				}
+				if _, ok := names[index]; ok || usedNames[fields[3]] {
+					return nil, fmt.Errorf("line %d: variable %d or name '%s' is already defined", line, index, fields[3])
+				}
+				names[index] = fields[3]
+				usedNames[fields[3]] = true
+			}
+			continue
+		case fields[0] == "p":
+			if nbrVariables >= 0 {
+				return nil, fmt.Errorf("line %d: duplicated problem line", line)
+			}
+
+			var err error
+			if len(fields) == 4 && fields[1] == "cnf" {
+				nbrVariables, err = strconv.Atoi(fields[2])
+				if err == nil {
+					nbrClauses, err = strconv.Atoi(fields[3])
+				}
+			}
+			if len(fields) != 4 || fields[1] != "cnf" || err != nil || nbrVariables < 0 || nbrClauses < 0 {
+				return nil, fmt.Errorf("line %d: invalid problem line, expected 'p cnf <variables> <clauses>'", line)
+			}
+			continue
+		case fields[0] == "%":
+			// end marker of the SATLIB benchmarks
+			return newDIMACSCNF(names, usedNames, nbrVariables, nbrClauses, clauses, clause)
+		}
+
+		if nbrVariables < 0 {
+			return nil, fmt.Errorf("line %d: the problem line must come before the clauses", line)
+		}
+
+		for _, field := range fields {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid literal '%s'", line, field)
+			}
+
+			literal := Literal(value)
+			if literal == 0 {
+				clauses = append(clauses, clause)
+				clause = Clause{}
+			} else if literal.Variable() > nbrVariables {
+				return nil, fmt.Errorf("line %d: the variable %d is above the %d variables of the problem", line, literal.Variable(), nbrVariables)
+			} else {
+				clause = append(clause, literal)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newDIMACSCNF(names, usedNames, nbrVariables, nbrClauses, clauses, clause)
+}
+
+func newDIMACSCNF(names map[int]string, usedNames map[string]bool, nbrVariables, nbrClauses int, clauses []Clause, clause Clause) (*CNF, error) {
+	if nbrVariables < 0 {
+		return nil, fmt.Errorf("missing problem line 'p cnf <variables> <clauses>'")
+	}
+
+	// the last clause may not be terminated by 0
+	if len(clause) > 0 {
+		clauses = append(clauses, clause)
+	}
+
+	if len(clauses) != nbrClauses {
+		return nil, fmt.Errorf("the problem declares %d clauses, found %d", nbrClauses, len(clauses))
+	}
+
+	variables := make([]string, nbrVariables)
+	for index := range variables {
+		name, ok := names[index+1]
+		if !ok {
+			name = fmt.Sprintf("x%d", index+1)
+			for usedNames[name] {
+				name = "_" + name
+			}
+		}
+		variables[index] = name
+	}
+
+	for index := range names {
+		if index > nbrVariables {
+			return nil, fmt.Errorf("the variable %d is named but above the %d variables of the problem", index, nbrVariables)
+		}
+	}
+
+	cnf := NewCNF(variables)
+	cnf.Clauses = clauses
+	return cnf, nil
+}
+
+/*
+Write an expression as a DIMACS CNF problem. The expression is converted with ToCNF,
+or with the Tseitin encoding when its CNF is too large, in which case the problem is
+only equisatisfiable. The names of the variables are written as comment lines
+*/
+func WriteDIMACS(writer io.Writer, expr Expression) error {
+	return WriteDIMACSWithEncoding(writer, expr, Totalizer)
+}
+
+/*
+Write an expression as a DIMACS CNF problem like WriteDIMACS, except that an expression
+with cardinality or pseudo-Boolean constraints is always converted with the Tseitin
+encoding, these constraints being encoded with the given cardinality encoding
+*/
+func WriteDIMACSWithEncoding(writer io.Writer, expr Expression, encoding CardinalityEncoding) error {
+	if hasLinearConstraints(expr) {
+		return TseitinCNFWithOptions(expr, CNFOptions{Cardinality: encoding}).WriteDIMACS(writer)
+	}
+
+	cnf, err := ToCNF(expr)
+	if err != nil {
+		cnf = TseitinCNF(expr)
+	}
+	return cnf.WriteDIMACS(writer)
+}
+
+/*
+Write the CNF as a DIMACS problem, with the names of the variables as comment lines
+*/
+func (cnf *CNF) WriteDIMACS(writer io.Writer) error {
+	buffer := bufio.NewWriter(writer)
+
+	for index, name := range cnf.Variables {
+		fmt.Fprintf(buffer, "c %s %d %s\n", DIMACS_VARIABLE_COMMENT, index+1, name)
+	}
+	fmt.Fprintf(buffer, "p cnf %d %d\n", len(cnf.Variables), len(cnf.Clauses))
+
+	for _, clause := range cnf.Clauses {
+		for _, literal := range clause {
+			fmt.Fprintf(buffer, "%d ", literal)
+		}
+		fmt.Fprintln(buffer, "0")
+	}
+
+	return buffer.Flush()
+}
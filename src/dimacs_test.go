@@ -0,0 +1,109 @@
+package logic
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDIMACS(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test simple problem", "p cnf 2 2\n1 -2 0\n2 0\n", "(x1 | !x2) & x2"},
+		{"test comments", "c a comment\nc var 1 a\np cnf 2 1\nc another comment\n1 2 0\n", "a | x2"},
+		{"test clause on several lines", "p cnf 3 1\n1\n-2\n3 0\n", "x1 | !x2 | x3"},
+		{"test several clauses on a line", "p cnf 2 2\n1 0 -2 0\n", "x1 & !x2"},
+		{"test missing final zero", "p cnf 1 1\n-1", "!x1"},
+		{"test end marker", "p cnf 1 1\n1 0\n%\n0\n", "x1"},
+		{"test empty clause", "p cnf 1 1\n0\n", "0"},
+		{"test no clause", "p cnf 1 0\n", "1"},
+		{"test default name already used", "c var 2 x1\np cnf 2 1\n1 2 0\n", "_x1 | x1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := ReadDIMACS(strings.NewReader(test.input))
+			assert.Nil(err)
+			assert.Equal(test.expected, expr.String(), test.name)
+		})
+	}
+}
+
+func TestReadDIMACSErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test missing problem line", "1 2 0\n", "line 1: the problem line must come before the clauses"},
+		{"test empty input", "", "missing problem line 'p cnf <variables> <clauses>'"},
+		{"test invalid problem line", "p dnf 2 1\n", "line 1: invalid problem line, expected 'p cnf <variables> <clauses>'"},
+		{"test duplicated problem line", "p cnf 1 0\np cnf 1 0\n", "line 2: duplicated problem line"},
+		{"test invalid literal", "p cnf 2 1\n1 a 0\n", "line 2: invalid literal 'a'"},
+		{"test unknown variable", "p cnf 2 1\n1 -3 0\n", "line 2: the variable 3 is above the 2 variables of the problem"},
+		{"test wrong number of clauses", "p cnf 2 2\n1 0\n", "the problem declares 2 clauses, found 1"},
+		{"test duplicated name", "c var 1 a\nc var 2 a\np cnf 2 0\n", "line 2: variable 2 or name 'a' is already defined"},
+		{"test invalid index", "c var 0 a\np cnf 2 0\n", "line 1: invalid variable index '0'"},
+		{"test named variable out of range", "c var 3 a\np cnf 2 0\n", "the variable 3 is named but above the 2 variables of the problem"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadDIMACS(strings.NewReader(test.input))
+			assert.NotNil(err)
+			if err != nil {
+				assert.Equal(test.expected, err.Error(), test.name)
+			}
+		})
+	}
+}
+
+func TestWriteDIMACS(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a | (b & !c)")
+	var buffer bytes.Buffer
+	assert.Nil(WriteDIMACS(&buffer, expr))
+	assert.Equal("c var 1 a\nc var 2 b\nc var 3 c\np cnf 3 2\n1 2 0\n1 -3 0\n", buffer.String())
+}
+
+func TestDIMACSRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(11))
+	variables := []string{"a", "b", "reset_n", "x1", "x12"}
+
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(random, 4, variables)
+
+		var buffer bytes.Buffer
+		assert.Nil(WriteDIMACS(&buffer, expr))
+
+		read, err := ReadDIMACS(&buffer)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, read), Print(expr, ASCII))
+	}
+}
+
+func TestDIMACSRoundTripTseitin(t *testing.T) {
+	assert := assert.New(t)
+
+	// too many clauses for the distributive conversion
+	expr, _ := parseInput("(a0 & b0) | (a1 & b1) | (a2 & b2) | (a3 & b3) | (a4 & b4) | (a5 & b5) | (a6 & b6) | (a7 & b7) | (a8 & b8) | (a9 & b9) | (a10 & b10) | (a11 & b11) | (a12 & b12) | (a13 & b13) | (a14 & b14)")
+	var buffer bytes.Buffer
+	assert.Nil(WriteDIMACS(&buffer, expr))
+
+	cnf, err := ReadDIMACSCNF(&buffer)
+	assert.Nil(err)
+	assert.Equal(TseitinCNF(expr).Variables, cnf.Variables)
+	assert.Equal(TseitinCNF(expr).Clauses, cnf.Clauses)
+}
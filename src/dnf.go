@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Conjunction of literals, numbered as the literals of a Clause
+type Term []Literal
+
+/*
+Disjunction of terms over named variables. The variable at index k of Variables is
+numbered k+1 in the terms
+*/
+type DNF struct {
+	Variables []string
+	Terms     []Term
+}
+
+/*
+Convert an expression into an equivalent DNF by distributing the conjunctions over the
+disjunctions. The terms containing a variable and its negation are removed, as well as
+the terms containing all the literals of another term. The number of terms can grow
+exponentially, so an error is returned above MAX_CNF_CLAUSES terms
+*/
+func ToDNF(expr Expression) (*DNF, error) {
+	// the terms of the expression are the negations of the clauses of its negation
+	cnf := NewCNF(Variables(expr))
+	clauses, err := distributiveClauses(cnf, expr, true)
+	if err != nil {
+		return nil, fmt.Errorf("the DNF has more than %d terms", MAX_CNF_CLAUSES)
+	}
+
+	dnf := &DNF{Variables: cnf.Variables, Terms: []Term{}}
+	for _, clause := range clauses {
+		term := Term{}
+		for _, literal := range clause {
+			term = append(term, literal.Not())
+		}
+		dnf.Terms = append(dnf.Terms, term)
+	}
+	return dnf, nil
+}
+
+/*
+Return the name of the variable of a literal
+*/
+func (dnf *DNF) Name(literal Literal) string {
+	return dnf.Variables[literal.Variable()-1]
+}
+
+/*
+Return true if a term has only true literals. Missing variables are false
+*/
+func (dnf *DNF) Eval(variables map[string]bool) bool {
+	for _, term := range dnf.Terms {
+		satisfied := true
+		for _, literal := range term {
+			if variables[dnf.Name(literal)] == literal.IsNegated() {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Return the DNF as a disjunction of conjunctions, 0 when it has no term
+*/
+func (dnf *DNF) ToExpression() Expression {
+	var expr Expression
+	for _, term := range dnf.Terms {
+		var conjunction Expression
+		for _, literal := range term {
+			var factor Expression = NewVarExpression(dnf.Name(literal))
+			if literal.IsNegated() {
+				factor = NewNotExpression(factor)
+			}
+
+			if conjunction == nil {
+				conjunction = factor
+			} else {
+				conjunction = NewAndExpression(conjunction, factor)
+			}
+		}
+
+		if conjunction == nil {
+			conjunction = NewNumberExpression(1)
+		}
+
+		if expr == nil {
+			expr = conjunction
+		} else {
+			expr = NewOrExpression(expr, conjunction)
+		}
+	}
+
+	if expr == nil {
+		return NewNumberExpression(0)
+	}
+	return expr
+}
+
+/*
+Return the terms in the ASCII dialect, without parentheses since AND binds tighter than OR
+*/
+func (dnf *DNF) String() string {
+	terms := make([]string, len(dnf.Terms))
+	for index, term := range dnf.Terms {
+		literals := make([]string, len(term))
+		for position, literal := range term {
+			literals[position] = dnf.Name(literal)
+			if literal.IsNegated() {
+				literals[position] = "!" + literals[position]
+			}
+		}
+		terms[index] = strings.Join(literals, " & ")
+		if len(term) == 0 {
+			terms[index] = "1"
+		}
+	}
+
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " | ")
+}
@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDNF(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test or", "a | b", "a | b"},
+		{"test distribution", "a & (b | c)", "a & b | a & c"},
+		{"test implies", "a -> b", "!a | b"},
+		{"test xor", "a + b", "a & !b | !a & b"},
+		{"test equivalence", "a <-> b", "!a & !b | a & b"},
+		{"test de morgan", "!(a & b)", "!a | !b"},
+		{"test tautology", "a | !a", "a | !a"},
+		{"test contradiction", "a & !a", "0"},
+		{"test subsumption", "(a & b) | a", "a"},
+		{"test constant", "1", "1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			dnf, err := ToDNF(expr)
+			assert.Nil(err)
+			assert.Equal(test.expected, dnf.String(), test.name)
+			assert.True(sameTruthTable(expr, dnf.ToExpression()), test.name)
+		})
+	}
+}
+
+func TestToDNFRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(35))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 5, variables)
+		dnf, err := ToDNF(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, dnf.ToExpression()), Print(expr, ASCII))
+
+		for row := 0; row < 1<<len(variables); row++ {
+			assignment := assignmentOf(row, variables)
+			assert.Equal(expr.Eval(assignment), dnf.Eval(assignment), Print(expr, ASCII))
+		}
+
+		// no term contains another one
+		for first, term := range dnf.Terms {
+			for second, other := range dnf.Terms {
+				assert.True(first == second || !isSubClause(Clause(term), Clause(other)), Print(expr, ASCII))
+			}
+		}
+	}
+}
+
+func TestToDNFTooLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	// (a0 | b0) & (a1 | b1) & ... has 2^n terms
+	expr, _ := parseInput("(a0 | b0) & (a1 | b1) & (a2 | b2) & (a3 | b3) & (a4 | b4) & (a5 | b5) & (a6 | b6) & (a7 | b7) & (a8 | b8) & (a9 | b9) & (a10 | b10) & (a11 | b11) & (a12 | b12) & (a13 | b13) & (a14 | b14)")
+	_, err := ToDNF(expr)
+	assert.Equal("the DNF has more than 10000 terms", err.Error())
+}
@@ -1,6 +1,35 @@
 package logic
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Kind of graph generated for an expression
+*/
+type GraphKind int
+
+const (
+	GraphTree GraphKind = iota // Parse tree of the expression
+	GraphBDD                   // Binary decision diagram of the expression
+)
+
+var graphKindNames = map[string]GraphKind{
+	"tree": GraphTree,
+	"bdd":  GraphBDD,
+}
+
+/*
+Return the kind of graph associated to a name (tree or bdd)
+*/
+func ParseGraphKind(name string) (GraphKind, error) {
+	if kind, ok := graphKindNames[strings.ToLower(name)]; ok {
+		return kind, nil
+	}
+
+	return GraphTree, fmt.Errorf("unknown diagram %s, expected tree or bdd", name)
+}
 
 func GenerateDot(expression Expression) string {
 	var builder strings.Builder
@@ -9,3 +38,168 @@ func GenerateDot(expression Expression) string {
 	builder.WriteString("}\n")
 	return builder.String()
 }
+
+/*
+Options of the DOT graph of a decision diagram
+*/
+type BDDDotOptions struct {
+	Order           BDDOrderHeuristic // Order of the variables
+	ComplementEdges bool              // Share the nodes of a function and of its negation
+}
+
+/*
+Generate the DOT graph of the decision diagram of an expression
+*/
+func GenerateBDDDot(expression Expression, options BDDDotOptions) string {
+	manager, root := NewBDD(expression, options.Order)
+	return manager.ToDot(root, options.ComplementEdges)
+}
+
+/*
+Generate the DOT graph of a decision diagram: the low edges are dashed, the high edges
+are solid, the terminals are boxes and the nodes of a variable are on the same rank.
+
+With complement edges, a node and its negation are drawn once: an edge ending with a
+small circle leads to the negation of the node it points to. As usual, the high edges
+are never complemented and only the terminal 1 is drawn
+*/
+func (manager *BDDManager) ToDot(root BDD, complementEdges bool) string {
+	graph := bddDotGraph{
+		manager: manager,
+		ranks:   make(map[int][]string),
+		nodes:   make(map[string]bool),
+		visited: make(map[BDD]dotEdge),
+	}
+	if complementEdges {
+		graph.complementIDs = make(map[complementNode]string)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("digraph BDD {\n")
+	builder.WriteString("\"root\" [shape=none, label=\"f\"];\n")
+
+	target, complemented := graph.visit(root, &builder)
+	builder.WriteString(fmt.Sprintf("\"root\" -> \"%s\"%s;\n", target, complementAttributes(complemented, false)))
+
+	for level := 0; level < len(manager.order); level++ {
+		if ids, ok := graph.ranks[level]; ok {
+			builder.WriteString(fmt.Sprintf("{ rank=same; \"%s\"; }\n", strings.Join(ids, "\"; \"")))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("{ rank=sink; \"%s\"; }\n", strings.Join(graph.terminals, "\"; \"")))
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+/*
+Node of a diagram with complement edges: a variable and its children, the low child
+being possibly complemented
+*/
+type complementNode struct {
+	variable      int
+	low, high     string
+	complementLow bool
+}
+
+// Edge leading to a node of the graph
+type dotEdge struct {
+	id           string
+	complemented bool
+}
+
+type bddDotGraph struct {
+	manager       *BDDManager
+	ranks         map[int][]string // Nodes of each level
+	terminals     []string
+	nodes         map[string]bool // Nodes already written
+	visited       map[BDD]dotEdge
+	complementIDs map[complementNode]string
+}
+
+/*
+Write a node and its descendants, and return its identifier, and whether the edge
+leading to it is complemented
+*/
+func (graph *bddDotGraph) visit(node BDD, builder *strings.Builder) (string, bool) {
+	if edge, ok := graph.visited[node]; ok {
+		return edge.id, edge.complemented
+	}
+
+	id, complemented := graph.write(node, builder)
+	graph.visited[node] = dotEdge{id: id, complemented: complemented}
+	return id, complemented
+}
+
+func (graph *bddDotGraph) write(node BDD, builder *strings.Builder) (string, bool) {
+	if node <= BDDTrue {
+		if graph.complementIDs != nil {
+			graph.writeTerminal("1", builder)
+			return "1", node == BDDFalse
+		}
+
+		id := fmt.Sprint(int(node))
+		graph.writeTerminal(id, builder)
+		return id, false
+	}
+
+	manager := graph.manager
+	current := manager.nodes[node]
+	lowID, complementLow := graph.visit(current.low, builder)
+	highID, complementHigh := graph.visit(current.high, builder)
+
+	id := fmt.Sprintf("n%d", node)
+	complemented := false
+	if graph.complementIDs != nil {
+		// f = x ? !h : l is drawn as the negation of x ? h : !l
+		if complementHigh {
+			complemented = true
+			complementLow = !complementLow
+		}
+
+		key := complementNode{variable: current.variable, low: lowID, high: highID, complementLow: complementLow}
+		if existing, ok := graph.complementIDs[key]; ok {
+			return existing, complemented
+		}
+		graph.complementIDs[key] = id
+	}
+
+	if graph.nodes[id] {
+		return id, complemented
+	}
+	graph.nodes[id] = true
+
+	level := manager.levels[current.variable]
+	graph.ranks[level] = append(graph.ranks[level], id)
+	builder.WriteString(fmt.Sprintf("\"%s\" [shape=circle, label=\"%s\"];\n", id, manager.variables[current.variable]))
+	builder.WriteString(fmt.Sprintf("\"%s\" -> \"%s\"%s;\n", id, lowID, complementAttributes(complementLow, true)))
+	builder.WriteString(fmt.Sprintf("\"%s\" -> \"%s\";\n", id, highID))
+	return id, complemented
+}
+
+func (graph *bddDotGraph) writeTerminal(id string, builder *strings.Builder) {
+	if graph.nodes[id] {
+		return
+	}
+	graph.nodes[id] = true
+	graph.terminals = append(graph.terminals, id)
+	builder.WriteString(fmt.Sprintf("\"%s\" [shape=box, label=\"%s\"];\n", id, id))
+}
+
+/*
+Return the DOT attributes of an edge, dashed for the low edges and ending with a small
+circle when complemented
+*/
+func complementAttributes(complemented, low bool) string {
+	attributes := []string{}
+	if low {
+		attributes = append(attributes, "style=dashed")
+	}
+	if complemented {
+		attributes = append(attributes, "arrowhead=odot")
+	}
+
+	if len(attributes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(attributes, ", "))
+}
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateBDDDot(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a & b")
+	expected := `digraph BDD {
+"root" [shape=none, label="f"];
+"0" [shape=box, label="0"];
+"1" [shape=box, label="1"];
+"n3" [shape=circle, label="b"];
+"n3" -> "0" [style=dashed];
+"n3" -> "1";
+"n4" [shape=circle, label="a"];
+"n4" -> "0" [style=dashed];
+"n4" -> "n3";
+"root" -> "n4";
+{ rank=same; "n4"; }
+{ rank=same; "n3"; }
+{ rank=sink; "0"; "1"; }
+}
+`
+	assert.Equal(expected, GenerateBDDDot(expr, BDDDotOptions{}))
+}
+
+func TestGenerateBDDDotComplementEdges(t *testing.T) {
+	assert := assert.New(t)
+
+	// below the root, the parity has two nodes per level, a node and its negation
+	expr, _ := parseInput("a + b + c")
+	dot := GenerateBDDDot(expr, BDDDotOptions{})
+	assert.Equal(5, strings.Count(dot, "shape=circle"))
+	assert.Equal(2, strings.Count(dot, "shape=box"))
+
+	dot = GenerateBDDDot(expr, BDDDotOptions{ComplementEdges: true})
+	assert.Equal(3, strings.Count(dot, "shape=circle"))
+	assert.Equal(1, strings.Count(dot, "shape=box"))
+	assert.Contains(dot, "arrowhead=odot")
+
+	// a negated function is drawn as a complemented edge to the root
+	expr, _ = parseInput("!(a & b)")
+	dot = GenerateBDDDot(expr, BDDDotOptions{ComplementEdges: true})
+	assert.Contains(dot, "\"root\" -> \"n6\" [arrowhead=odot];")
+	assert.Equal(2, strings.Count(dot, "shape=circle"))
+
+	expr, _ = parseInput("a & !a")
+	dot = GenerateBDDDot(expr, BDDDotOptions{ComplementEdges: true})
+	assert.Contains(dot, "\"root\" -> \"1\" [arrowhead=odot];")
+}
+
+func TestGenerateBDDDotOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a & (b | c) & (c + d) & (c -> a)")
+	dot := GenerateBDDDot(expr, BDDDotOptions{Order: BDDOrderOccurrence})
+	assert.True(strings.Index(dot, "label=\"c\"") > strings.Index(dot, "label=\"d\""))
+	assert.Contains(dot, "{ rank=same; \"n")
+}
+
+func TestParseGraphKind(t *testing.T) {
+	assert := assert.New(t)
+
+	kind, err := ParseGraphKind("BDD")
+	assert.Nil(err)
+	assert.Equal(GraphBDD, kind)
+
+	kind, err = ParseGraphKind("tree")
+	assert.Nil(err)
+	assert.Equal(GraphTree, kind)
+
+	_, err = ParseGraphKind("forest")
+	assert.Equal("unknown diagram forest, expected tree or bdd", err.Error())
+}
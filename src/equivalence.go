@@ -0,0 +1,17 @@
+package logic
+
+/*
+Decide whether two expressions are logically equivalent, unlike the structural
+comparison of equal: "a ^ b" and "b ^ a" are equivalent. The SAT solver searches a
+model of the miter a XOR b, which is an assignment on which the expressions differ.
+
+When they are not equivalent, the counterexample gives the values of the variables
+of both expressions
+*/
+func Equivalent(a, b Expression) (bool, map[string]bool) {
+	counterexample, ok := Solve(NewXORExpression(a, b))
+	if ok {
+		return false, counterexample
+	}
+	return true, nil
+}
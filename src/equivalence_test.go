@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquivalent(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		first    string
+		second   string
+		expected bool
+	}{
+		{"test commutative and", "a ^ b", "b ^ a", true},
+		{"test de morgan", "!(a | b)", "!a & !b", true},
+		{"test implication", "a -> b", "!a | b", true},
+		{"test distributivity", "a & (b | c)", "a & b | a & c", true},
+		{"test constants", "a | !a", "1", true},
+		{"test different", "a & b", "a | b", false},
+		{"test missing variable", "a", "a & b", false},
+		{"test other variables", "a & !a", "b & !b", true},
+		{"test negation", "a + b", "a <-> b", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first, _ := parseInput(test.first)
+			second, _ := parseInput(test.second)
+			equivalent, counterexample := Equivalent(first, second)
+			assert.Equal(test.expected, equivalent)
+
+			if test.expected {
+				assert.Nil(counterexample)
+			} else {
+				assert.NotEqual(first.Eval(counterexample), second.Eval(counterexample))
+				assert.ElementsMatch(variablesOf(first, second), keysOf(counterexample))
+			}
+		})
+	}
+}
+
+func TestEquivalentRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(24))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 300; i++ {
+		first := randomExpression(random, 4, variables)
+		second := randomExpression(random, 4, variables)
+		if i%3 == 0 {
+			second = first.Simplify()
+		}
+
+		equivalent, counterexample := Equivalent(first, second)
+		assert.Equal(sameTruthTable(first, second), equivalent)
+		if !equivalent {
+			assert.NotEqual(first.Eval(counterexample), second.Eval(counterexample))
+		}
+	}
+}
+
+func keysOf(assignment map[string]bool) []string {
+	keys := []string{}
+	for key := range assignment {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,707 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// Maximal number of variables accepted by the heuristic minimizer
+const MAX_HEURISTIC_VARIABLES = 64
+
+// Maximal number of cubes of the covers built by the heuristic minimizer
+const MAX_HEURISTIC_CUBES = 10000
+
+type EspressoEffort int
+
+// Quality and time trade-off of the heuristic minimizer
+const (
+	EspressoFast       EspressoEffort = iota // A single EXPAND and IRREDUNDANT pass
+	EspressoNormal                           // REDUCE, EXPAND and IRREDUNDANT until the cover stops improving
+	EspressoExhaustive                       // EspressoNormal followed by LAST_GASP passes to escape local minima
+)
+
+var espressoEffortNames = map[string]EspressoEffort{
+	"fast":       EspressoFast,
+	"normal":     EspressoNormal,
+	"exhaustive": EspressoExhaustive,
+}
+
+/*
+Return the effort associated to a name (fast, normal or exhaustive)
+*/
+func ParseEspressoEffort(name string) (EspressoEffort, error) {
+	if effort, ok := espressoEffortNames[strings.ToLower(name)]; ok {
+		return effort, nil
+	}
+
+	return EspressoNormal, fmt.Errorf("unknown espresso effort %s, expected fast, normal or exhaustive", name)
+}
+
+/*
+Return the name of the effort
+*/
+func (effort EspressoEffort) String() string {
+	for name, value := range espressoEffortNames {
+		if value == effort {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+/*
+Minimize an expression into a small sum of products with an Espresso-like heuristic.
+The expression is turned into covers of cubes, without building its truth table,
+so that expressions with many variables can be minimized. The result is not
+guaranteed to be minimal, use Minimize for an exact result on small expressions.
+An error is returned when a cover grows above MAX_HEURISTIC_CUBES cubes, which
+happens with long chains of XOR or equivalences
+*/
+func MinimizeHeuristic(expr Expression, effort EspressoEffort) (Expression, error) {
+	return MinimizeHeuristicWithDontCares(expr, NewNumberExpression(0), effort)
+}
+
+/*
+Minimize an expression with the heuristic minimizer, where the rows for which
+dontCares is true can not occur: the result can take any value on these rows
+*/
+func MinimizeHeuristicWithDontCares(expr, dontCares Expression, effort EspressoEffort) (Expression, error) {
+	variables := variablesOf(expr, dontCares)
+	if len(variables) > MAX_HEURISTIC_VARIABLES {
+		return nil, fmt.Errorf("the heuristic minimizer supports at most %d variables, found %d", MAX_HEURISTIC_VARIABLES, len(variables))
+	}
+
+	indexes := indexVariables(variables)
+	on, err := coverOf(expr, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	dontCareCover, err := coverOf(dontCares, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	cover := espresso(on, dontCareCover, effort)
+	return coverToExpression(cover, variables), nil
+}
+
+func indexVariables(variables []string) map[string]int {
+	indexes := make(map[string]int, len(variables))
+	for index, variable := range variables {
+		indexes[variable] = index
+	}
+	return indexes
+}
+
+/*
+Compute the cover of the rows where the expression is true. Negated parts of the
+expression are complemented with the unate recursive paradigm
+*/
+func coverOf(expr Expression, indexes map[string]int) ([]cube, error) {
+	switch value := expr.(type) {
+	case *VarExpression:
+		bit := uint64(1) << indexes[value.variable]
+		return []cube{{value: bit, mask: bit}}, nil
+	case *NumberExpression:
+		if value.value == 0 {
+			return []cube{}, nil
+		}
+		return []cube{{}}, nil
+	case *NotExpression:
+		cover, err := coverOf(value.expr, indexes)
+		if err != nil {
+			return nil, err
+		}
+		return coverComplement(cover)
+	case *CardinalityExpression, *PseudoBooleanExpression:
+		return coverOf(expandLinearConstraint(expr), indexes)
+	}
+
+	children := operands(expr)
+	left, err := coverOf(children[0], indexes)
+	if err != nil {
+		return nil, err
+	}
+	right, err := coverOf(children[1], indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.(type) {
+	case *AndExpression:
+		return coverProduct(left, right)
+	case *OrExpression:
+		return coverUnion(left, right)
+	}
+
+	notLeft, err := coverComplement(left)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := expr.(*ImpliesExpression); ok {
+		return coverUnion(notLeft, right)
+	}
+
+	notRight, err := coverComplement(right)
+	if err != nil {
+		return nil, err
+	}
+
+	// XOR is l.!r | !l.r, equivalence is l.r | !l.!r
+	first, second := [2][]cube{left, notRight}, [2][]cube{notLeft, right}
+	if _, ok := expr.(*EquivalenceExpression); ok {
+		first, second = [2][]cube{left, right}, [2][]cube{notLeft, notRight}
+	}
+
+	firstProduct, err := coverProduct(first[0], first[1])
+	if err != nil {
+		return nil, err
+	}
+	secondProduct, err := coverProduct(second[0], second[1])
+	if err != nil {
+		return nil, err
+	}
+	return coverUnion(firstProduct, secondProduct)
+}
+
+/*
+Run the Espresso loop on the on-set cover. Instead of computing the off-set, which
+explodes for large functions, an expansion is accepted when the expanded cube is still
+covered by the on-set and the don't care set
+*/
+func espresso(on, dontCares []cube, effort EspressoEffort) []cube {
+	allowed := append(append([]cube{}, on...), dontCares...)
+	cover := expand(removeContainedCubes(on), allowed)
+	cover = irredundant(cover, dontCares)
+	if effort == EspressoFast {
+		return cover
+	}
+
+	for {
+		for {
+			products, literals := coverCost(cover)
+			candidate := irredundant(expand(reduce(cover, dontCares), allowed), dontCares)
+			if !isCheaperCover(candidate, products, literals) {
+				break
+			}
+			cover = candidate
+		}
+
+		if effort != EspressoExhaustive {
+			return cover
+		}
+
+		products, literals := coverCost(cover)
+		candidate := lastGasp(cover, allowed, dontCares)
+		if !isCheaperCover(candidate, products, literals) {
+			return cover
+		}
+		cover = candidate
+	}
+}
+
+/*
+Make each cube as large as possible while staying inside the allowed cover, and
+remove the cubes covered by the expanded ones
+*/
+func expand(cover, allowed []cube) []cube {
+	cubes := append([]cube{}, cover...)
+	// the smallest cubes are the least likely to be covered by the others
+	sort.SliceStable(cubes, func(i, j int) bool {
+		return cubes[i].literals() > cubes[j].literals()
+	})
+
+	expanded := []cube{}
+	covered := make([]bool, len(cubes))
+	for index, c := range cubes {
+		if covered[index] {
+			continue
+		}
+
+		c = expandCube(c, cubes, allowed)
+		for other := index + 1; other < len(cubes); other++ {
+			if !covered[other] && c.covers(cubes[other]) {
+				covered[other] = true
+			}
+		}
+		expanded = append(expanded, c)
+	}
+
+	return removeContainedCubes(expanded)
+}
+
+/*
+Raise the literals of a cube one by one, starting with the literals that are the
+most often missing or opposite in the other cubes
+*/
+func expandCube(c cube, cubes, allowed []cube) cube {
+	literals := []uint64{}
+	scores := make(map[uint64]int)
+	for remaining := c.mask; remaining != 0; remaining &= remaining - 1 {
+		bit := remaining & -remaining
+		literals = append(literals, bit)
+		for _, other := range cubes {
+			if other.mask&bit == 0 || other.value&bit != c.value&bit {
+				scores[bit]++
+			}
+		}
+	}
+
+	sort.SliceStable(literals, func(i, j int) bool {
+		return scores[literals[i]] > scores[literals[j]]
+	})
+
+	for _, bit := range literals {
+		raised := cube{value: c.value &^ bit, mask: c.mask &^ bit}
+		if coverContainsCube(allowed, raised) {
+			c = raised
+		}
+	}
+	return c
+}
+
+/*
+Remove the cubes covered by the other cubes and the don't care cover
+*/
+func irredundant(cover, dontCares []cube) []cube {
+	cubes := append([]cube{}, cover...)
+	// try to remove the smallest cubes first
+	sort.SliceStable(cubes, func(i, j int) bool {
+		return cubes[i].literals() > cubes[j].literals()
+	})
+
+	for index := 0; index < len(cubes); {
+		others := append(append([]cube{}, cubes[:index]...), cubes[index+1:]...)
+		if coverContainsCube(append(others, dontCares...), cubes[index]) {
+			cubes = others
+		} else {
+			index++
+		}
+	}
+	return cubes
+}
+
+/*
+Shrink each cube to the smallest cube still covering the rows that only it covers,
+to let the next expansion move in another direction
+*/
+func reduce(cover, dontCares []cube) []cube {
+	cubes := append([]cube{}, cover...)
+	// reduce the largest cubes first
+	sort.SliceStable(cubes, func(i, j int) bool {
+		return cubes[i].literals() < cubes[j].literals()
+	})
+
+	for index := 0; index < len(cubes); {
+		others := append(append([]cube{}, cubes[:index]...), cubes[index+1:]...)
+		reduced, ok := reduceCube(cubes[index], append(others, dontCares...))
+		if !ok {
+			cubes = others
+			continue
+		}
+		cubes[index] = reduced
+		index++
+	}
+	return cubes
+}
+
+/*
+Return the smallest cube containing the rows of c not covered by the others,
+or false when all the rows of c are covered. A literal belongs to this cube when
+the rows of c with the opposite literal are all covered by the others, so only the
+literals of the uncovered rows found along the way need to be checked
+*/
+func reduceCube(c cube, others []cube) (cube, bool) {
+	cofactor := coverCofactor(others, c)
+	row, found := uncoveredCube(cofactor)
+	if !found {
+		return c, false
+	}
+
+	candidates := row.mask
+	for candidates != 0 {
+		bit := candidates & -candidates
+		candidates &^= bit
+
+		opposite := cube{value: ^row.value & bit, mask: bit}
+		other, found := uncoveredCube(coverCofactor(cofactor, opposite))
+		if !found {
+			c = cube{value: c.value | row.value&bit, mask: c.mask | bit}
+			continue
+		}
+
+		// the literals differing between two uncovered rows can not be added
+		candidates &= other.mask &^ (other.value ^ row.value)
+	}
+	return c, true
+}
+
+/*
+Reduce every cube independently, expand the reduced cubes and keep them if they
+make a cheaper cover
+*/
+func lastGasp(cover, allowed, dontCares []cube) []cube {
+	reduced := []cube{}
+	for index, c := range cover {
+		others := append(append([]cube{}, cover[:index]...), cover[index+1:]...)
+		if smaller, ok := reduceCube(c, append(others, dontCares...)); ok && smaller != c {
+			reduced = append(reduced, smaller)
+		}
+	}
+
+	if len(reduced) == 0 {
+		return cover
+	}
+
+	candidates := expand(reduced, allowed)
+	return irredundant(removeContainedCubes(append(candidates, cover...)), dontCares)
+}
+
+func coverCost(cover []cube) (int, int) {
+	literals := 0
+	for _, c := range cover {
+		literals += c.literals()
+	}
+	return len(cover), literals
+}
+
+func isCheaperCover(cover []cube, products, literals int) bool {
+	newProducts, newLiterals := coverCost(cover)
+	return newProducts < products || (newProducts == products && newLiterals < literals)
+}
+
+// Cover algebra
+
+func intersectsCover(c cube, cover []cube) bool {
+	for _, other := range cover {
+		if c.intersects(other) {
+			return true
+		}
+	}
+	return false
+}
+
+func coverTooLargeError() error {
+	return fmt.Errorf("the expression is too large for the heuristic minimizer, a cover exceeds %d cubes", MAX_HEURISTIC_CUBES)
+}
+
+func coverUnion(first, second []cube) ([]cube, error) {
+	if len(first)+len(second) > MAX_HEURISTIC_CUBES {
+		return nil, coverTooLargeError()
+	}
+	return removeContainedCubes(append(append([]cube{}, first...), second...)), nil
+}
+
+func coverProduct(first, second []cube) ([]cube, error) {
+	product := []cube{}
+	for _, a := range first {
+		for _, b := range second {
+			if !a.intersects(b) {
+				continue
+			}
+			if len(product) == MAX_HEURISTIC_CUBES {
+				return nil, coverTooLargeError()
+			}
+			product = append(product, cube{value: a.value | b.value, mask: a.mask | b.mask})
+		}
+	}
+	return removeContainedCubes(product), nil
+}
+
+/*
+Remove the cubes contained in another cube of the cover
+*/
+func removeContainedCubes(cover []cube) []cube {
+	cubes := append([]cube{}, cover...)
+	sort.SliceStable(cubes, func(i, j int) bool {
+		return cubes[i].literals() < cubes[j].literals()
+	})
+
+	kept := []cube{}
+	for _, c := range cubes {
+		contained := false
+		for _, other := range kept {
+			if other.covers(c) {
+				contained = true
+				break
+			}
+		}
+
+		if !contained {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+/*
+Restrict the cover to the rows of c, and remove the literals of c from its cubes
+*/
+func coverCofactor(cover []cube, c cube) []cube {
+	cofactor := make([]cube, 0, len(cover))
+	for _, other := range cover {
+		if other.intersects(c) {
+			cofactor = append(cofactor, cube{value: other.value &^ c.mask, mask: other.mask &^ c.mask})
+		}
+	}
+	return cofactor
+}
+
+/*
+Return true if every row of c is covered by the cover
+*/
+func coverContainsCube(cover []cube, c cube) bool {
+	for _, other := range cover {
+		if other.covers(c) {
+			return true
+		}
+	}
+	return isTautology(coverCofactor(cover, c))
+}
+
+/*
+Return true if the cover contains every row, using the unate recursive paradigm
+*/
+func isTautology(cover []cube) bool {
+	// the cubes can not fill the space if their volumes are too small
+	volume := 0.0
+	for _, c := range cover {
+		volume += math.Ldexp(1, -c.literals())
+	}
+	if volume < 1 {
+		return false
+	}
+
+	_, found := uncoveredCube(cover)
+	return !found
+}
+
+/*
+Return the mask of the variables appearing with a single polarity in the cover
+*/
+func unateVariables(cover []cube) uint64 {
+	var positive, negative uint64
+	for _, c := range cover {
+		positive |= c.value
+		negative |= c.mask &^ c.value
+	}
+	return positive ^ negative
+}
+
+/*
+Return a cube of rows that are not covered by the cover, or false when the cover is
+a tautology. It follows the same steps as isTautology, keeping track of the literals
+fixed along the way
+*/
+func uncoveredCube(cover []cube) (cube, bool) {
+	if len(cover) == 0 {
+		return cube{}, true
+	}
+
+	var units cube
+	for _, c := range cover {
+		if c.mask == 0 {
+			return cube{}, false
+		}
+		if c.literals() != 1 {
+			continue
+		}
+		if units.mask&c.mask != 0 && units.value&c.mask != c.value {
+			return cube{}, false
+		}
+		units = cube{value: units.value | c.value, mask: units.mask | c.mask}
+	}
+	if units.mask != 0 {
+		opposite := cube{value: ^units.value & units.mask, mask: units.mask}
+		row, found := uncoveredCube(coverCofactor(cover, opposite))
+		return cube{value: row.value | opposite.value, mask: row.mask | opposite.mask}, found
+	}
+
+	if unate := unateVariables(cover); unate != 0 {
+		// set the unate variables against their polarity
+		var positive uint64
+		reduced := []cube{}
+		for _, c := range cover {
+			positive |= c.value
+			if c.mask&unate == 0 {
+				reduced = append(reduced, c)
+			}
+		}
+		row, found := uncoveredCube(reduced)
+		return cube{value: row.value | unate&^positive, mask: row.mask | unate}, found
+	}
+
+	if components := coverComponents(cover); len(components) > 1 {
+		product := cube{}
+		for _, component := range components {
+			row, found := uncoveredCube(component)
+			if !found {
+				return cube{}, false
+			}
+			product = cube{value: product.value | row.value, mask: product.mask | row.mask}
+		}
+		return product, true
+	}
+
+	bit, _ := splittingVariable(cover)
+	for _, literal := range []cube{{value: bit, mask: bit}, {value: 0, mask: bit}} {
+		if row, found := uncoveredCube(coverCofactor(cover, literal)); found {
+			return cube{value: row.value | literal.value, mask: row.mask | literal.mask}, true
+		}
+	}
+	return cube{}, false
+}
+
+/*
+Split the cover into groups of cubes sharing no variable with the other groups
+*/
+func coverComponents(cover []cube) [][]cube {
+	supports := []uint64{}
+	for _, c := range cover {
+		merged := c.mask
+		kept := supports[:0]
+		for _, support := range supports {
+			if support&merged != 0 {
+				merged |= support
+			} else {
+				kept = append(kept, support)
+			}
+		}
+		supports = append(kept, merged)
+	}
+
+	if len(supports) == 1 {
+		return [][]cube{cover}
+	}
+
+	components := make([][]cube, len(supports))
+	for _, c := range cover {
+		for index, support := range supports {
+			if c.mask&support != 0 {
+				components[index] = append(components[index], c)
+				break
+			}
+		}
+	}
+	return components
+}
+
+/*
+Return the complement of a cover, computed by splitting on the most binate variables.
+An error is returned as soon as a partial complement exceeds MAX_HEURISTIC_CUBES cubes
+*/
+func coverComplement(cover []cube) ([]cube, error) {
+	if len(cover) == 0 {
+		return []cube{{}}, nil
+	}
+
+	for _, c := range cover {
+		if c.mask == 0 {
+			return []cube{}, nil
+		}
+	}
+
+	if len(cover) == 1 {
+		// De Morgan: one cube per opposite literal
+		complement := []cube{}
+		c := cover[0]
+		for remaining := c.mask; remaining != 0; remaining &= remaining - 1 {
+			bit := remaining & -remaining
+			complement = append(complement, cube{value: ^c.value & bit, mask: bit})
+		}
+		return complement, nil
+	}
+
+	bit, _ := splittingVariable(cover)
+	positive, err := coverComplement(coverCofactor(cover, cube{value: bit, mask: bit}))
+	if err != nil {
+		return nil, err
+	}
+	negative, err := coverComplement(coverCofactor(cover, cube{value: 0, mask: bit}))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(positive)+len(negative) > MAX_HEURISTIC_CUBES {
+		return nil, coverTooLargeError()
+	}
+
+	complement := []cube{}
+	for _, c := range positive {
+		complement = append(complement, cube{value: c.value | bit, mask: c.mask | bit})
+	}
+	for _, c := range negative {
+		complement = append(complement, cube{value: c.value, mask: c.mask | bit})
+	}
+	return mergeAdjacentCubes(complement, bit), nil
+}
+
+/*
+Merge the cubes that only differ by the literal of bit, then remove the contained cubes
+*/
+func mergeAdjacentCubes(cover []cube, bit uint64) []cube {
+	positive := make(map[cube]bool)
+	negative := make(map[cube]bool)
+	for _, c := range cover {
+		raised := cube{value: c.value &^ bit, mask: c.mask &^ bit}
+		if c.value&bit != 0 {
+			positive[raised] = true
+		} else {
+			negative[raised] = true
+		}
+	}
+
+	merged := []cube{}
+	for _, c := range cover {
+		raised := cube{value: c.value &^ bit, mask: c.mask &^ bit}
+		if c.mask&bit != 0 && positive[raised] && negative[raised] {
+			// keep a single copy of the merged cube
+			if c.value&bit == 0 {
+				merged = append(merged, raised)
+			}
+			continue
+		}
+		merged = append(merged, c)
+	}
+	return removeContainedCubes(merged)
+}
+
+/*
+Return the variable appearing the most often in the cover, preferring the variables
+appearing with both polarities. The boolean is false if the cover is unate
+*/
+func splittingVariable(cover []cube) (uint64, bool) {
+	var positive, negative [64]int
+	for _, c := range cover {
+		for remaining := c.mask; remaining != 0; remaining &= remaining - 1 {
+			index := bits.TrailingZeros64(remaining)
+			if c.value&(1<<index) != 0 {
+				positive[index]++
+			} else {
+				negative[index]++
+			}
+		}
+	}
+
+	best, bestScore, binate := -1, -1, false
+	for index := 0; index < 64; index++ {
+		score := positive[index] + negative[index]
+		isBinate := positive[index] > 0 && negative[index] > 0
+		if score == 0 || (binate && !isBinate) {
+			continue
+		}
+
+		if (isBinate && !binate) || score > bestScore {
+			best, bestScore, binate = index, score, isBinate
+		}
+	}
+
+	if best < 0 {
+		return 0, false
+	}
+	return uint64(1) << best, binate
+}
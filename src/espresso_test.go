@@ -0,0 +1,214 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Generate a random sum of products over many variables, like the rules of a configurator
+*/
+func randomRuleSet(random *rand.Rand, nbrVariables, nbrProducts, nbrLiterals int) Expression {
+	var expr Expression
+	for product := 0; product < nbrProducts; product++ {
+		var term Expression
+		for literal := 0; literal < nbrLiterals; literal++ {
+			var variable Expression = NewVarExpression(fmt.Sprintf("x%d", random.Intn(nbrVariables)))
+			if random.Intn(2) == 0 {
+				variable = NewNotExpression(variable)
+			}
+
+			if term == nil {
+				term = variable
+			} else {
+				term = NewAndExpression(term, variable)
+			}
+		}
+
+		if expr == nil {
+			expr = term
+		} else {
+			expr = NewOrExpression(expr, term)
+		}
+	}
+	return expr
+}
+
+func TestMinimizeHeuristic(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test adjacent products", "a&b | a&!b", "a"},
+		{"test consensus", "a&b | !a&c | b&c", "a & b | !a & c"},
+		{"test tautology", "a | !a", "1"},
+		{"test contradiction", "a & !a", "0"},
+		{"test equivalence", "a <-> b", "a & b | !a & !b"},
+	}
+
+	for _, effort := range []EspressoEffort{EspressoFast, EspressoNormal, EspressoExhaustive} {
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%s with %s effort", test.name, effort), func(t *testing.T) {
+				expr, err := parseInput(test.input)
+				assert.Nil(err)
+
+				minimized, err := MinimizeHeuristic(expr, effort)
+				assert.Nil(err)
+				assert.Equal(test.expected, minimized.String(), fmt.Sprintf("%s with %s effort", test.name, effort))
+			})
+		}
+	}
+}
+
+func TestMinimizeHeuristicRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(11))
+	variables := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(random, 5, variables)
+		for _, effort := range []EspressoEffort{EspressoFast, EspressoNormal, EspressoExhaustive} {
+			minimized, err := MinimizeHeuristic(expr, effort)
+			assert.Nil(err)
+			assert.True(sameTruthTable(expr, minimized), expr.String())
+		}
+
+		exact, _ := Minimize(expr)
+		heuristic, _ := MinimizeHeuristic(expr, EspressoExhaustive)
+		exactProducts, _ := sumOfProductsCost(exact)
+		heuristicProducts, _ := sumOfProductsCost(heuristic)
+		assert.LessOrEqual(exactProducts, heuristicProducts)
+	}
+}
+
+/*
+Check a minimization on random assignments, the truth table being too large
+*/
+func assertSameOnRandomAssignments(assert *assert.Assertions, random *rand.Rand, expr, minimized Expression) {
+	variables := variablesOf(expr, minimized)
+	for i := 0; i < 2000; i++ {
+		assignment := make(map[string]bool)
+		for _, variable := range variables {
+			assignment[variable] = random.Intn(2) == 0
+		}
+		assert.Equal(expr.Eval(assignment), minimized.Eval(assignment))
+	}
+}
+
+func TestMinimizeHeuristicManyVariables(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(3))
+
+	tests := []struct {
+		name                                   string
+		nbrVariables, nbrProducts, nbrLiterals int
+	}{
+		{"test 50 variables", 50, 60, 6},
+		{"test 60 variables with long products", 60, 200, 8},
+		{"test 60 variables with short products", 60, 300, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr := randomRuleSet(random, test.nbrVariables, test.nbrProducts, test.nbrLiterals)
+			minimized, err := MinimizeHeuristic(expr, EspressoNormal)
+			assert.Nil(err)
+
+			products, _ := sumOfProductsCost(minimized)
+			assert.LessOrEqual(products, test.nbrProducts)
+			assertSameOnRandomAssignments(assert, random, expr, minimized)
+		})
+	}
+}
+
+func TestMinimizeHeuristicTooLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	// the parity of 32 variables needs 2^31 products
+	var expr Expression = NewVarExpression("x0")
+	for index := 1; index < 32; index++ {
+		expr = NewXORExpression(expr, NewVarExpression(fmt.Sprintf("x%d", index)))
+	}
+
+	_, err := MinimizeHeuristic(expr, EspressoFast)
+	assert.NotNil(err)
+}
+
+func TestParseEspressoEffort(t *testing.T) {
+	assert := assert.New(t)
+
+	effort, err := ParseEspressoEffort("Exhaustive")
+	assert.Nil(err)
+	assert.Equal(EspressoExhaustive, effort)
+	assert.Equal("exhaustive", effort.String())
+
+	_, err = ParseEspressoEffort("slow")
+	assert.NotNil(err)
+}
+
+func BenchmarkMinimizeHeuristic(b *testing.B) {
+	random := rand.New(rand.NewSource(9))
+	expr := randomRuleSet(random, 60, 300, 4)
+
+	for _, effort := range []EspressoEffort{EspressoFast, EspressoNormal, EspressoExhaustive} {
+		b.Run(effort.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				MinimizeHeuristic(expr, effort)
+			}
+		})
+	}
+}
+
+func TestCoverComplement(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		expr := randomExpression(random, 4, []string{"a", "b", "c", "d"})
+		variables := Variables(expr)
+		cover, err := coverOf(expr, indexVariables(variables))
+		assert.Nil(err)
+		complement, err := coverComplement(cover)
+		assert.Nil(err)
+
+		for row := 0; row < 1<<len(variables); row++ {
+			assert.Equal(!expr.Eval(assignmentOf(row, variables)), intersectsCover(mintermCube(row, len(variables)), complement))
+		}
+	}
+}
+
+func TestMinimizeHeuristicWithDontCares(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(13))
+	variables := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(random, 4, variables)
+		dontCares := randomExpression(random, 3, variables)
+
+		for _, effort := range []EspressoEffort{EspressoFast, EspressoNormal, EspressoExhaustive} {
+			minimized, err := MinimizeHeuristicWithDontCares(expr, dontCares, effort)
+			assert.Nil(err)
+
+			all := variablesOf(expr, dontCares, minimized)
+			for row := 0; row < 1<<len(all); row++ {
+				assignment := assignmentOf(row, all)
+				if !dontCares.Eval(assignment) {
+					assert.Equal(expr.Eval(assignment), minimized.Eval(assignment), expr.String())
+				}
+			}
+		}
+	}
+
+	expr, _ := parseInput("a&!b | !a&b")
+	dontCares, _ := parseInput("a&b")
+	minimized, err := MinimizeHeuristicWithDontCares(expr, dontCares, EspressoNormal)
+	assert.Nil(err)
+	assert.Equal("a | b", minimized.String())
+}
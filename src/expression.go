@@ -63,7 +63,7 @@ func (notExpr *NotExpression) Simplify() Expression {
 }
 
 func (notExprt NotExpression) String() string {
-	return fmt.Sprintf("!%s", notExprt.expr)
+	return Print(&notExprt, ASCII)
 }
 
 func (notExpr *NotExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -101,7 +101,7 @@ func (varExpr *VarExpression) Simplify() Expression {
 }
 
 func (varExpr VarExpression) String() string {
-	return varExpr.variable
+	return Print(&varExpr, ASCII)
 }
 
 func (varExpr *VarExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -180,7 +180,7 @@ func (orExpr *OrExpression) Simplify() Expression {
 }
 
 func (orExpr OrExpression) String() string {
-	return fmt.Sprintf("%sv%s", orExpr.left, orExpr.right)
+	return Print(&orExpr, ASCII)
 }
 
 func (orExpr *OrExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -280,7 +280,7 @@ func (andExpr AndExpression) isDomination(left, right Expression) bool {
 }
 
 func (andExpr AndExpression) String() string {
-	return fmt.Sprintf("%s^%s", andExpr.left, andExpr.right)
+	return Print(&andExpr, ASCII)
 }
 
 func (andExpr *AndExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -322,7 +322,7 @@ func (impliesExpr *ImpliesExpression) Simplify() Expression {
 }
 
 func (impliesExpr ImpliesExpression) String() string {
-	return fmt.Sprintf("%s->%s", impliesExpr.left, impliesExpr.right)
+	return Print(&impliesExpr, ASCII)
 }
 
 func (impliesExpr *ImpliesExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -401,7 +401,7 @@ func (xorExpr *XORExpression) Simplify() Expression {
 }
 
 func (xorExpr XORExpression) String() string {
-	return fmt.Sprintf("%s⊕%s", xorExpr.left, xorExpr.right)
+	return Print(&xorExpr, ASCII)
 }
 
 func (xorExpr *XORExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -441,7 +441,7 @@ func (nbrExpr *NumberExpression) Simplify() Expression {
 }
 
 func (nbrExpr NumberExpression) String() string {
-	return fmt.Sprintf("%d", nbrExpr.value)
+	return Print(&nbrExpr, ASCII)
 }
 
 func (nbrExpr *NumberExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -506,7 +506,7 @@ func (equivalenceExpr *EquivalenceExpression) Simplify() Expression {
 }
 
 func (equivalenceExpression EquivalenceExpression) String() string {
-	return fmt.Sprintf("%s<->%s", equivalenceExpression.left, equivalenceExpression.right)
+	return Print(&equivalenceExpression, ASCII)
 }
 
 func (equivalenceExpr *EquivalenceExpression) ToDot(builder *strings.Builder, parentID string) {
@@ -0,0 +1,513 @@
+package logic
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	boolutil "github.com/dterbah/go-logic/src/utils"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Number of variables of the Karnaugh maps
+const (
+	MIN_KMAP_VARIABLES = 2
+	MAX_KMAP_VARIABLES = 6
+)
+
+// Size in pixels of a cell of the SVG Karnaugh maps
+const KMAP_SVG_CELL_SIZE = 48
+
+/*
+Output format of a Karnaugh map
+*/
+type KMapFormat int
+
+const (
+	KMapNone     KMapFormat = iota
+	KMapTerminal            // Table of the terminal, colored with ANSI escape codes
+	KMapMarkdown            // Markdown table
+	KMapHTML                // HTML table
+	KMapSVG                 // SVG image, the groups being drawn as rectangles
+)
+
+var kmapFormatNames = map[KMapFormat]string{
+	KMapNone:     "none",
+	KMapTerminal: "terminal",
+	KMapMarkdown: "markdown",
+	KMapHTML:     "html",
+	KMapSVG:      "svg",
+}
+
+func (format KMapFormat) String() string {
+	return kmapFormatNames[format]
+}
+
+/*
+Return the format of Karnaugh map associated to a name (terminal, markdown, html or svg)
+*/
+func ParseKMapFormat(name string) (KMapFormat, error) {
+	for format, formatName := range kmapFormatNames {
+		if format != KMapNone && strings.EqualFold(name, formatName) {
+			return format, nil
+		}
+	}
+
+	return KMapNone, fmt.Errorf("unknown Karnaugh map format %s, expected terminal, markdown, html or svg", name)
+}
+
+// Colors of the groups, as ANSI codes for the terminal and as RGB for HTML and SVG
+var kmapColors = []struct{ ansi, rgb string }{
+	{"31", "#e6194b"},
+	{"34", "#4363d8"},
+	{"32", "#3cb44b"},
+	{"35", "#911eb4"},
+	{"33", "#f58231"},
+	{"36", "#42d4f4"},
+	{"91", "#f032e6"},
+	{"96", "#469990"},
+}
+
+/*
+Options of the output of a Karnaugh map
+*/
+type KMapOptions struct {
+	Format  KMapFormat
+	Colors  bool    // Color the terminal output with ANSI escape codes
+	Dialect Dialect // Dialect of the groups listed under the map
+}
+
+/*
+Karnaugh map of a function of 2 to 6 variables. The rows and the columns follow the Gray
+code, so that adjacent cells, including the ones on opposite edges, differ by one variable
+*/
+type KarnaughMap struct {
+	Variables       []string   // Variables of the truth table, the variable k being the bit k of the rows
+	RowVariables    []string   // Variables of the rows of the map, from the most significant bit
+	ColumnVariables []string   // Variables of the columns of the map, from the most significant bit
+	Cells           [][]string // Values of the cells: 0, 1 or DONT_CARE_VALUE
+	Groups          []Cube     // Groups overlaid on the map, such as the cubes chosen by a minimizer
+
+	// rows of the truth table with only the variables of a row or of a column of the map
+	rowMinterms, columnMinterms []int
+}
+
+/*
+Rectangle of cells of a Karnaugh map. A group wrapping around an edge of the map is made
+of several rectangles
+*/
+type KMapRectangle struct {
+	Row, Column, Height, Width int
+}
+
+/*
+Create the Karnaugh map of the expression over the variables. The cells for which
+dontCares is true are don't cares, dontCares being ignored when nil
+*/
+func NewKarnaughMap(expr, dontCares Expression, variables []string) (*KarnaughMap, error) {
+	if err := checkKMapVariables(variables); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 1<<len(variables))
+	for row := range values {
+		assignment := assignmentOf(row, variables)
+		if dontCares != nil && dontCares.Eval(assignment) {
+			values[row] = DONT_CARE_VALUE
+		} else {
+			values[row] = boolutil.BoolToString(expr.Eval(assignment))
+		}
+	}
+	return NewKarnaughMapFromValues(variables, values)
+}
+
+/*
+Create the Karnaugh map of the values of the truth table of the variables, such as
+0, 1 or DONT_CARE_VALUE. The value of the row k is at the index k, as by Minterms
+*/
+func NewKarnaughMapFromValues(variables []string, values []string) (*KarnaughMap, error) {
+	if err := checkKMapVariables(variables); err != nil {
+		return nil, err
+	}
+	if len(values) != 1<<len(variables) {
+		return nil, fmt.Errorf("the truth table of %d variables has %d rows, found %d", len(variables), 1<<len(variables), len(values))
+	}
+
+	// the most significant variables are on the rows
+	reversed := slices.Clone(variables)
+	slices.Reverse(reversed)
+	kmap := &KarnaughMap{
+		Variables:       variables,
+		RowVariables:    reversed[:len(variables)/2],
+		ColumnVariables: reversed[len(variables)/2:],
+		Groups:          []Cube{},
+	}
+
+	indexes := indexVariables(variables)
+	kmap.rowMinterms = grayMinterms(kmap.RowVariables, indexes)
+	kmap.columnMinterms = grayMinterms(kmap.ColumnVariables, indexes)
+	for _, rowMinterm := range kmap.rowMinterms {
+		row := []string{}
+		for _, columnMinterm := range kmap.columnMinterms {
+			row = append(row, values[rowMinterm|columnMinterm])
+		}
+		kmap.Cells = append(kmap.Cells, row)
+	}
+	return kmap, nil
+}
+
+func checkKMapVariables(variables []string) error {
+	if len(variables) < MIN_KMAP_VARIABLES || len(variables) > MAX_KMAP_VARIABLES {
+		return fmt.Errorf("a Karnaugh map has from %d to %d variables, found %d", MIN_KMAP_VARIABLES, MAX_KMAP_VARIABLES, len(variables))
+	}
+	return nil
+}
+
+/*
+Return the rows of the truth table set by the successive Gray codes of the variables
+*/
+func grayMinterms(variables []string, indexes map[string]int) []int {
+	minterms := []int{}
+	for position := 0; position < 1<<len(variables); position++ {
+		code := position ^ (position >> 1)
+		minterm := 0
+		for index, variable := range variables {
+			if code&(1<<(len(variables)-1-index)) != 0 {
+				minterm |= 1 << indexes[variable]
+			}
+		}
+		minterms = append(minterms, minterm)
+	}
+	return minterms
+}
+
+/*
+Add a group to overlay on the map. The group must be a cube over the variables of the map
+*/
+func (kmap *KarnaughMap) AddGroup(group Cube) error {
+	if !slices.Equal(group.Variables(), kmap.Variables) {
+		return fmt.Errorf("the group %s is not defined over the variables of the map", group)
+	}
+	kmap.Groups = append(kmap.Groups, group)
+	return nil
+}
+
+/*
+Return the row of the truth table of a cell of the map
+*/
+func (kmap *KarnaughMap) Minterm(row, column int) int {
+	return kmap.rowMinterms[row] | kmap.columnMinterms[column]
+}
+
+/*
+Return the rectangles of cells covered by a group, from the top left corner. A group
+wrapping around an edge is split at this edge
+*/
+func (kmap *KarnaughMap) Rectangles(group Cube) []KMapRectangle {
+	rectangles := []KMapRectangle{}
+	for _, rows := range kmapSegments(kmap.rowMinterms, group.bits) {
+		for _, columns := range kmapSegments(kmap.columnMinterms, group.bits) {
+			rectangles = append(rectangles, KMapRectangle{Row: rows.start, Column: columns.start, Height: rows.size, Width: columns.size})
+		}
+	}
+	return rectangles
+}
+
+/*
+Consecutive positions of an axis covered by a group. The segment continues on the other
+edge of the map when before or after is set
+*/
+type kmapSegment struct {
+	start, size   int
+	before, after bool
+}
+
+func kmapSegments(minterms []int, group cube) []kmapSegment {
+	// the minterms of an axis only set the variables of this axis
+	axisMask := uint64(0)
+	for _, minterm := range minterms {
+		axisMask |= uint64(minterm)
+	}
+
+	covered := make([]bool, len(minterms))
+	for position, minterm := range minterms {
+		covered[position] = (uint64(minterm)^group.value)&group.mask&axisMask == 0
+	}
+
+	segments := []kmapSegment{}
+	for position := 0; position < len(covered); position++ {
+		if !covered[position] {
+			continue
+		}
+
+		segment := kmapSegment{start: position}
+		for position < len(covered) && covered[position] {
+			segment.size, position = segment.size+1, position+1
+		}
+		segments = append(segments, segment)
+	}
+
+	last := len(covered) - 1
+	if len(segments) > 1 && covered[0] && covered[last] {
+		segments[0].before = true
+		segments[len(segments)-1].after = true
+	}
+	return segments
+}
+
+/*
+Write the map in a format. The groups are listed under the map, each one with a label
+shown in the cells it covers, except in SVG where they are drawn as rectangles
+*/
+func (kmap *KarnaughMap) Write(writer io.Writer, options KMapOptions) error {
+	switch options.Format {
+	case KMapTerminal:
+		return kmap.writeTerminal(writer, options)
+	case KMapMarkdown:
+		return kmap.writeMarkdown(writer, options)
+	case KMapHTML:
+		return kmap.writeHTML(writer, options)
+	case KMapSVG:
+		return kmap.writeSVG(writer, options)
+	}
+	return fmt.Errorf("unknown Karnaugh map format %d", options.Format)
+}
+
+func (kmap *KarnaughMap) writeTerminal(writer io.Writer, options KMapOptions) error {
+	colored := func(code, text string) string {
+		if !options.Colors {
+			return text
+		}
+		return fmt.Sprintf("\033[%sm%s\033[0m", code, text)
+	}
+	valueColors := map[string]string{"0": "2", "1": "1;32", DONT_CARE_VALUE: "33"}
+
+	table := tablewriter.NewWriter(writer)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetHeader(append([]string{kmap.corner()}, axisLabels(len(kmap.ColumnVariables))...))
+	for row, label := range axisLabels(len(kmap.RowVariables)) {
+		line := []string{label}
+		for column, value := range kmap.Cells[row] {
+			cell := colored(valueColors[value], value)
+			for _, index := range kmap.groupsOf(row, column) {
+				cell += " " + colored("1;"+kmapColors[index%len(kmapColors)].ansi, kmapGroupLabel(index))
+			}
+			line = append(line, cell)
+		}
+		table.Append(line)
+	}
+	table.Render()
+
+	for index, group := range kmap.Groups {
+		label := colored("1;"+kmapColors[index%len(kmapColors)].ansi, kmapGroupLabel(index))
+		if _, err := fmt.Fprintf(writer, "%s : %s\n", label, Print(group.ToExpression(), options.Dialect)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kmap *KarnaughMap) writeMarkdown(writer io.Writer, options KMapOptions) error {
+	escape := func(text string) string {
+		return strings.ReplaceAll(text, "|", "\\|")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("| " + escape(kmap.corner()) + " |")
+	for _, label := range axisLabels(len(kmap.ColumnVariables)) {
+		builder.WriteString(" " + label + " |")
+	}
+	builder.WriteString("\n|---|" + strings.Repeat(":-:|", len(kmap.columnMinterms)) + "\n")
+
+	for row, label := range axisLabels(len(kmap.RowVariables)) {
+		builder.WriteString("| **" + label + "** |")
+		for column, value := range kmap.Cells[row] {
+			labels := []string{}
+			for _, index := range kmap.groupsOf(row, column) {
+				labels = append(labels, kmapGroupLabel(index))
+			}
+
+			builder.WriteString(" " + value)
+			if len(labels) > 0 {
+				builder.WriteString(" (" + strings.Join(labels, ", ") + ")")
+			}
+			builder.WriteString(" |")
+		}
+		builder.WriteString("\n")
+	}
+
+	if len(kmap.Groups) > 0 {
+		builder.WriteString("\n")
+	}
+	for index, group := range kmap.Groups {
+		builder.WriteString(fmt.Sprintf("- **%s** : `%s`\n", kmapGroupLabel(index), Print(group.ToExpression(), options.Dialect)))
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func (kmap *KarnaughMap) writeHTML(writer io.Writer, options KMapOptions) error {
+	groupLabel := func(index int) string {
+		return fmt.Sprintf(`<span style="color: %s; font-weight: bold">%s</span>`, kmapColors[index%len(kmapColors)].rgb, kmapGroupLabel(index))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("<table class=\"kmap\">\n  <tr><th>" + html.EscapeString(kmap.corner()) + "</th>")
+	for _, label := range axisLabels(len(kmap.ColumnVariables)) {
+		builder.WriteString("<th>" + label + "</th>")
+	}
+	builder.WriteString("</tr>\n")
+
+	for row, label := range axisLabels(len(kmap.RowVariables)) {
+		builder.WriteString("  <tr><th>" + label + "</th>")
+		for column, value := range kmap.Cells[row] {
+			builder.WriteString("<td>" + html.EscapeString(value))
+			for _, index := range kmap.groupsOf(row, column) {
+				builder.WriteString(" " + groupLabel(index))
+			}
+			builder.WriteString("</td>")
+		}
+		builder.WriteString("</tr>\n")
+	}
+	builder.WriteString("</table>\n")
+
+	if len(kmap.Groups) > 0 {
+		builder.WriteString("<ul class=\"kmap-groups\">\n")
+		for index, group := range kmap.Groups {
+			builder.WriteString(fmt.Sprintf("  <li>%s : <code>%s</code></li>\n", groupLabel(index), html.EscapeString(Print(group.ToExpression(), options.Dialect))))
+		}
+		builder.WriteString("</ul>\n")
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func (kmap *KarnaughMap) writeSVG(writer io.Writer, options KMapOptions) error {
+	size := KMAP_SVG_CELL_SIZE
+	corner := html.EscapeString(kmap.corner())
+	left := max(size, 10*len(kmap.corner())+16)
+	top := 2 * size
+	width := left + len(kmap.columnMinterms)*size + size/2
+	height := top + len(kmap.rowMinterms)*size + size/2 + len(kmap.Groups)*size/2
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\" font-size=\"16\">\n", width, height, width, height))
+	builder.WriteString(fmt.Sprintf("  <rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height))
+	builder.WriteString(fmt.Sprintf("  <defs><clipPath id=\"kmap-cells\"><rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/></clipPath></defs>\n",
+		left, top, len(kmap.columnMinterms)*size, len(kmap.rowMinterms)*size))
+	builder.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", left-8, top-size+16, corner))
+
+	for column, label := range axisLabels(len(kmap.ColumnVariables)) {
+		builder.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", left+column*size+size/2, top-8, label))
+	}
+	for row, label := range axisLabels(len(kmap.RowVariables)) {
+		builder.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", left-8, top+row*size+size/2+6, label))
+		for column := range kmap.columnMinterms {
+			builder.WriteString(fmt.Sprintf("  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"black\"/>\n", left+column*size, top+row*size, size, size))
+		}
+	}
+
+	// the groups are drawn inside their cells, a bit more inside for each group so that they
+	// do not overlap, and a group continuing on the other edge is left open at the edge
+	for index, group := range kmap.Groups {
+		color := kmapColors[index%len(kmapColors)].rgb
+		inset := 4 + 3*(index%4)
+		for _, rows := range kmapSegments(kmap.rowMinterms, group.bits) {
+			for _, columns := range kmapSegments(kmap.columnMinterms, group.bits) {
+				x, y := left+columns.start*size+inset, top+rows.start*size+inset
+				w, h := columns.size*size-2*inset, rows.size*size-2*inset
+				if columns.before {
+					x, w = x-size, w+size
+				}
+				if columns.after {
+					w += size
+				}
+				if rows.before {
+					y, h = y-size, h+size
+				}
+				if rows.after {
+					h += size
+				}
+				builder.WriteString(fmt.Sprintf("  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"12\" fill=\"%s\" fill-opacity=\"0.15\" stroke=\"%s\" stroke-width=\"2\" clip-path=\"url(#kmap-cells)\"/>\n",
+					x, y, w, h, color, color))
+			}
+		}
+	}
+
+	for row := range kmap.rowMinterms {
+		for column, value := range kmap.Cells[row] {
+			builder.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", left+column*size+size/2, top+row*size+size/2+6, html.EscapeString(value)))
+		}
+	}
+
+	for index, group := range kmap.Groups {
+		y := top + len(kmap.rowMinterms)*size + size/2 + index*size/2
+		color := kmapColors[index%len(kmapColors)].rgb
+		builder.WriteString(fmt.Sprintf("  <rect x=\"%d\" y=\"%d\" width=\"16\" height=\"16\" rx=\"4\" fill=\"%s\" fill-opacity=\"0.15\" stroke=\"%s\" stroke-width=\"2\"/>\n", 8, y-14, color, color))
+		builder.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\">%s : %s</text>\n", 32, y, kmapGroupLabel(index), html.EscapeString(Print(group.ToExpression(), options.Dialect))))
+	}
+	builder.WriteString("</svg>\n")
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+/*
+Return the indexes of the groups covering a cell
+*/
+func (kmap *KarnaughMap) groupsOf(row, column int) []int {
+	indexes := []int{}
+	for index, group := range kmap.Groups {
+		if group.bits.containsMinterm(uint64(kmap.Minterm(row, column))) {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+/*
+Return the title of the top left cell, such as "dc \ ba" for the variables d, c on the
+rows and b, a on the columns
+*/
+func (kmap *KarnaughMap) corner() string {
+	return axisName(kmap.RowVariables) + ` \ ` + axisName(kmap.ColumnVariables)
+}
+
+func axisName(variables []string) string {
+	for _, variable := range variables {
+		if len(variable) > 1 {
+			return strings.Join(variables, ",")
+		}
+	}
+	return strings.Join(variables, "")
+}
+
+/*
+Return the successive Gray codes of a number of bits, such as 00, 01, 11, 10
+*/
+func axisLabels(nbrBits int) []string {
+	labels := []string{}
+	for position := 0; position < 1<<nbrBits; position++ {
+		labels = append(labels, fmt.Sprintf("%0*b", nbrBits, position^(position>>1)))
+	}
+	return labels
+}
+
+/*
+Return the label of a group: A to Z, then a to z, then numbers
+*/
+func kmapGroupLabel(index int) string {
+	switch {
+	case index < 26:
+		return string(rune('A' + index))
+	case index < 52:
+		return string(rune('a' + index - 26))
+	}
+	return fmt.Sprint(index + 1)
+}
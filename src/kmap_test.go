@@ -0,0 +1,203 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKMapFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, format := range []KMapFormat{KMapTerminal, KMapMarkdown, KMapHTML, KMapSVG} {
+		parsed, err := ParseKMapFormat(format.String())
+		assert.Nil(err)
+		assert.Equal(format, parsed)
+	}
+
+	_, err := ParseKMapFormat("none")
+	assert.Equal("unknown Karnaugh map format none, expected terminal, markdown, html or svg", err.Error())
+}
+
+func TestKarnaughMapLayout(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name            string
+		input           string
+		variables       []string
+		expectedRows    []string
+		expectedColumns []string
+		expectedCells   [][]string
+	}{
+		{"test 2 variables", "a & !b", []string{"a", "b"}, []string{"b"}, []string{"a"}, [][]string{{"0", "1"}, {"0", "0"}}},
+		{"test 3 variables", "c | a & b", []string{"a", "b", "c"}, []string{"c"}, []string{"b", "a"}, [][]string{{"0", "0", "1", "0"}, {"1", "1", "1", "1"}}},
+		{"test 4 variables", "a + d", []string{"a", "b", "c", "d"}, []string{"d", "c"}, []string{"b", "a"}, [][]string{
+			{"0", "1", "1", "0"},
+			{"0", "1", "1", "0"},
+			{"1", "0", "0", "1"},
+			{"1", "0", "0", "1"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			kmap, err := NewKarnaughMap(expr, nil, test.variables)
+			assert.Nil(err)
+			assert.Equal(test.expectedRows, kmap.RowVariables, test.name)
+			assert.Equal(test.expectedColumns, kmap.ColumnVariables, test.name)
+			assert.Equal(test.expectedCells, kmap.Cells, test.name)
+		})
+	}
+}
+
+func TestKarnaughMapGrayCode(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(40))
+
+	for nbrVariables := MIN_KMAP_VARIABLES; nbrVariables <= MAX_KMAP_VARIABLES; nbrVariables++ {
+		variables := []string{}
+		for index := 0; index < nbrVariables; index++ {
+			variables = append(variables, fmt.Sprintf("x%d", index))
+		}
+		expr := randomExpression(random, 4, variables)
+		kmap, err := NewKarnaughMap(expr, nil, variables)
+		assert.Nil(err)
+
+		// every row appears once, and adjacent cells differ by one variable, also across the edges
+		seen := make(map[int]bool)
+		height, width := len(kmap.Cells), len(kmap.Cells[0])
+		assert.Equal(1<<nbrVariables, height*width)
+		for row := 0; row < height; row++ {
+			for column := 0; column < width; column++ {
+				minterm := kmap.Minterm(row, column)
+				seen[minterm] = true
+				assert.Equal(expr.Eval(assignmentOf(minterm, variables)), kmap.Cells[row][column] == "1")
+
+				right, below := kmap.Minterm(row, (column+1)%width), kmap.Minterm((row+1)%height, column)
+				if width > 1 {
+					assert.Equal(1, onesCount(minterm^right))
+				}
+				if height > 1 {
+					assert.Equal(1, onesCount(minterm^below))
+				}
+			}
+		}
+		assert.Equal(1<<nbrVariables, len(seen))
+	}
+}
+
+func onesCount(value int) int {
+	count := 0
+	for ; value != 0; value &= value - 1 {
+		count++
+	}
+	return count
+}
+
+func TestKarnaughMapRectangles(t *testing.T) {
+	assert := assert.New(t)
+	variables := []string{"a", "b", "c", "d"}
+	kmap, err := NewKarnaughMap(NewNumberExpression(1), nil, variables)
+	assert.Nil(err)
+
+	tests := []struct {
+		name     string
+		literals map[string]bool
+		expected []KMapRectangle
+	}{
+		{"test single cell", map[string]bool{"a": true, "b": true, "c": true, "d": true}, []KMapRectangle{{Row: 2, Column: 2, Height: 1, Width: 1}}},
+		{"test middle", map[string]bool{"a": true, "d": true}, []KMapRectangle{{Row: 2, Column: 1, Height: 2, Width: 2}}},
+		{"test wrap-around columns", map[string]bool{"a": false, "c": true}, []KMapRectangle{{Row: 1, Column: 0, Height: 2, Width: 1}, {Row: 1, Column: 3, Height: 2, Width: 1}}},
+		{"test corners", map[string]bool{"a": false, "c": false}, []KMapRectangle{
+			{Row: 0, Column: 0, Height: 1, Width: 1},
+			{Row: 0, Column: 3, Height: 1, Width: 1},
+			{Row: 3, Column: 0, Height: 1, Width: 1},
+			{Row: 3, Column: 3, Height: 1, Width: 1},
+		}},
+		{"test whole map", map[string]bool{}, []KMapRectangle{{Row: 0, Column: 0, Height: 4, Width: 4}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			group, err := NewCube(variables, test.literals)
+			assert.Nil(err)
+			assert.Equal(test.expected, kmap.Rectangles(group), test.name)
+		})
+	}
+
+	other, _ := NewCube([]string{"a", "b"}, map[string]bool{"a": true})
+	assert.Equal("the group a is not defined over the variables of the map", kmap.AddGroup(other).Error())
+}
+
+func TestKarnaughMapWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("!a & !c | b & d")
+	variables := []string{"a", "b", "c", "d"}
+	kmap, err := NewKarnaughMap(expr, nil, variables)
+	assert.Nil(err)
+
+	minimized, err := Minimize(expr)
+	assert.Nil(err)
+	groups, err := CubesOf(minimized, variables)
+	assert.Nil(err)
+	for _, group := range groups {
+		assert.Nil(kmap.AddGroup(group))
+	}
+
+	var buffer bytes.Buffer
+	assert.Nil(kmap.Write(&buffer, KMapOptions{Format: KMapMarkdown}))
+	assert.Equal(strings.Join([]string{
+		`| dc \ ba | 00 | 01 | 11 | 10 |`,
+		`|---|:-:|:-:|:-:|:-:|`,
+		`| **00** | 1 (A) | 0 | 0 | 1 (A) |`,
+		`| **01** | 0 | 0 | 0 | 0 |`,
+		`| **11** | 0 | 0 | 1 (B) | 1 (B) |`,
+		`| **10** | 1 (A) | 0 | 1 (B) | 1 (A, B) |`,
+		``,
+		"- **A** : `!a & !c`",
+		"- **B** : `b & d`",
+		``,
+	}, "\n"), buffer.String())
+
+	buffer.Reset()
+	assert.Nil(kmap.Write(&buffer, KMapOptions{Format: KMapTerminal}))
+	assert.Contains(buffer.String(), "| 1 A B |")
+	assert.NotContains(buffer.String(), "\033[")
+
+	buffer.Reset()
+	assert.Nil(kmap.Write(&buffer, KMapOptions{Format: KMapTerminal, Colors: true}))
+	assert.Contains(buffer.String(), "\033[1;32m1\033[0m")
+
+	buffer.Reset()
+	assert.Nil(kmap.Write(&buffer, KMapOptions{Format: KMapHTML, Dialect: Unicode}))
+	assert.Contains(buffer.String(), "<th>dc \\ ba</th>")
+	assert.Contains(buffer.String(), "<code>¬a ∧ ¬c</code>")
+
+	// the corners group is drawn as four rectangles, open on the edges of the map
+	buffer.Reset()
+	assert.Nil(kmap.Write(&buffer, KMapOptions{Format: KMapSVG}))
+	svg := buffer.String()
+	assert.True(strings.HasPrefix(svg, "<svg "))
+	assert.Equal(4, strings.Count(svg, `stroke="#e6194b" stroke-width="2" clip-path`))
+	assert.Equal(1, strings.Count(svg, `stroke="#4363d8" stroke-width="2" clip-path`))
+	assert.Contains(svg, "A : !a &amp; !c")
+}
+
+func TestKarnaughMapErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewKarnaughMap(NewVarExpression("a"), nil, []string{"a"})
+	assert.Equal("a Karnaugh map has from 2 to 6 variables, found 1", err.Error())
+
+	_, err = NewKarnaughMapFromValues([]string{"a", "b"}, []string{"0", "1"})
+	assert.Equal("the truth table of 2 variables has 4 rows, found 2", err.Error())
+}
@@ -2,7 +2,9 @@ package logic
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/dterbah/gods/list"
 	"github.com/dterbah/gods/list/arraylist"
@@ -12,31 +14,75 @@ type TokenType int
 
 // Alphabet of tokens
 const (
-	ILLEGAL     TokenType = iota
-	EOF                   // When no token available
-	VAR                   // variable
-	AND                   // &, ., ^
-	OR                    // |, v
-	XOR                   // +
-	NOT                   // !
-	LPAREN                // (
-	RPAREN                // )
-	IMPLIES               // ->
-	NUMBER                // 1, 0
-	EQUIVALENCE           // <->
+	ILLEGAL       TokenType = iota
+	EOF                     // When no token available
+	VAR                     // variable (identifier such as a, reset_n, x12)
+	AND                     // &, ., ^, &&, ∧, and
+	OR                      // |, v, ||, ∨, or
+	XOR                     // +, ⊕, ⊻, xor
+	NOT                     // !, ~, ¬, not
+	LPAREN                  // (
+	RPAREN                  // )
+	IMPLIES                 // ->, →, ⇒, implies
+	NUMBER                  // 1, 0, ⊤, ⊥, true, false, and the integers of the cardinality constraints
+	EQUIVALENCE             // <->, ↔, ⇔, iff
+	EQUAL                   // ==, equivalence binding tighter than AND as in C
+	NOT_EQUAL               // !=, XOR binding tighter than AND as in C
+	COMMA                   // , between the arguments of atleast, atmost and exactly
+	GREATER_EQUAL           // >=, ≥ of the pseudo-Boolean constraints
+	LESS_EQUAL              // <=, ≤ of the pseudo-Boolean constraints
 )
 
+// Keywords are case-insensitive and can not be used as variables
+var keywords = map[string]Token{
+	"and":     {Type: AND, Value: "AND"},
+	"or":      {Type: OR, Value: "OR"},
+	"not":     {Type: NOT, Value: "NOT"},
+	"xor":     {Type: XOR, Value: "XOR"},
+	"implies": {Type: IMPLIES, Value: "->"},
+	"iff":     {Type: EQUIVALENCE, Value: "<->"},
+	"true":    {Type: NUMBER, Value: "1"},
+	"false":   {Type: NUMBER, Value: "0"},
+}
+
+// C-style operators made of two characters
+var doubleCharOperators = map[string]Token{
+	"&&": {Type: AND, Value: "AND"},
+	"||": {Type: OR, Value: "OR"},
+	"==": {Type: EQUAL, Value: "=="},
+	"!=": {Type: NOT_EQUAL, Value: "!="},
+	">=": {Type: GREATER_EQUAL, Value: ">="},
+	"<=": {Type: LESS_EQUAL, Value: "<="},
+}
+
+// Defines the position of a character in the input
+type Position struct {
+	Offset int // Byte offset, starting at 0
+	Line   int // Line number, starting at 1
+	Column int // Column number in characters, starting at 1
+}
+
+// Defines the part of the input covered by a token. End is exclusive
+type Span struct {
+	Start Position
+	End   Position
+}
+
 // Defines the Token struct
 type Token struct {
 	Type  TokenType // Type associated to the token
 	Value string    // The associated value
+	Span  Span      // Location of the token in the input
 }
 
 // Defines the Lexer struct
 type Lexer struct {
-	input  string
-	pos    int
-	tokens *arraylist.ArrayList[Token]
+	input                string
+	pos                  int
+	line                 int
+	lineStart            int
+	tokens               *arraylist.ArrayList[Token]
+	allowDottedVariables bool
 }
 
 /*
@@ -52,92 +98,297 @@ func NewLexer(input string) *Lexer {
 	}
 
 	list := arraylist.New(comparator)
-	return &Lexer{input: input, tokens: list}
+	return &Lexer{input: input, tokens: list, line: 1}
+}
+
+/*
+Allow dotted variable names such as user.isAdmin. When enabled, a '.' placed
+between two identifier characters is part of the variable instead of an AND operator
+*/
+func (lexer *Lexer) WithDottedVariables() *Lexer {
+	lexer.allowDottedVariables = true
+	return lexer
 }
 
 /*
-Parse the input of the Lexer and create Token for each elements
+Parse the input of the Lexer and create Token for each elements.
+The returned error is a *ParseError locating the invalid characters
 */
 func (lexer *Lexer) Tokenize() (list.List[Token], error) {
 	if len(lexer.input) == 0 {
-		return nil, fmt.Errorf("the input should not be empty")
+		return nil, lexer.errorAt(0, 0, "the input should not be empty")
 	}
 
 	for lexer.pos < len(lexer.input) {
-		char := lexer.input[lexer.pos]
+		start := lexer.pos
+		char, size := utf8.DecodeRuneInString(lexer.input[lexer.pos:])
+
+		if operator, ok := doubleCharOperators[lexer.peek(2)]; ok {
+			lexer.pos += 2
+			lexer.addToken(operator.Type, operator.Value, start)
+			continue
+		}
 
 		switch {
-		case unicode.IsSpace(rune(char)):
+		case char == '\n':
 			lexer.pos++
-			continue
+			lexer.line++
+			lexer.lineStart = lexer.pos
+		case unicode.IsSpace(char):
+			lexer.pos += size
+		case isIdentifierStart(char):
+			token := lexer.readIdentifier()
+			lexer.addToken(token.Type, token.Value, start)
 		case isAndOperator(char):
-			lexer.tokens.Add(Token{Type: AND, Value: "AND"})
+			lexer.pos += size
+			lexer.addToken(AND, "AND", start)
 		case isOrOperator(char):
-			lexer.tokens.Add(Token{Type: OR, Value: "OR"})
+			lexer.pos += size
+			lexer.addToken(OR, "OR", start)
 		case isNotOperator(char):
-			lexer.tokens.Add(Token{Type: NOT, Value: "NOT"})
+			lexer.pos += size
+			lexer.addToken(NOT, "NOT", start)
 		case char == '(':
-			lexer.tokens.Add(Token{Type: LPAREN, Value: "("})
+			lexer.pos++
+			lexer.addToken(LPAREN, "(", start)
 		case char == ')':
-			lexer.tokens.Add(Token{Type: RPAREN, Value: ")"})
+			lexer.pos++
+			lexer.addToken(RPAREN, ")", start)
 		case isXOROperator(char):
-			lexer.tokens.Add(Token{Type: XOR, Value: "XOR"})
-		case isNumber(char):
-			lexer.tokens.Add(Token{Type: NUMBER, Value: string(char)})
-		case char == '<':
-			if lexer.pos+2 >= len(lexer.input) {
-				return nil, fmt.Errorf("erorr when analyzing equivalence operator")
-			}
-			dash := lexer.input[lexer.pos+1]
-			rightArrow := lexer.input[lexer.pos+2]
-
-			if dash != '-' {
-				return nil, fmt.Errorf("error when analyzing equivalence operator, found %s, expected '-'", string(dash))
+			lexer.pos += size
+			lexer.addToken(XOR, "XOR", start)
+		case isImpliesOperator(char):
+			lexer.pos += size
+			lexer.addToken(IMPLIES, "->", start)
+		case isEquivalenceOperator(char):
+			lexer.pos += size
+			lexer.addToken(EQUIVALENCE, "<->", start)
+		case isDigit(char):
+			for lexer.pos < len(lexer.input) && isDigit(rune(lexer.input[lexer.pos])) {
+				lexer.pos++
 			}
-
-			if rightArrow != '>' {
-				return nil, fmt.Errorf("error when analyzing equivalence operator, found %s, expected '>'", string(rightArrow))
+			lexer.addToken(NUMBER, lexer.input[start:lexer.pos], start)
+		case char == ',':
+			lexer.pos++
+			lexer.addToken(COMMA, ",", start)
+		case char == '≥':
+			lexer.pos += size
+			lexer.addToken(GREATER_EQUAL, ">=", start)
+		case char == '≤':
+			lexer.pos += size
+			lexer.addToken(LESS_EQUAL, "<=", start)
+		case isTrue(char):
+			lexer.pos += size
+			lexer.addToken(NUMBER, "1", start)
+		case isFalse(char):
+			lexer.pos += size
+			lexer.addToken(NUMBER, "0", start)
+		case char == '<':
+			if err := lexer.expect(start, "equivalence operator", '<', '-', '>'); err != nil {
+				return nil, err
 			}
-			lexer.pos += 2
-			lexer.tokens.Add(Token{Type: EQUIVALENCE, Value: "<->"})
-
+			lexer.addToken(EQUIVALENCE, "<->", start)
 		case char == '-':
-			lexer.pos++
-			if lexer.pos >= len(lexer.input) || lexer.input[lexer.pos] != '>' {
-				return nil, fmt.Errorf("error when analyzing implies operator, found %s, expected '>'", string(lexer.input[lexer.pos]))
+			if err := lexer.expect(start, "implies operator", '-', '>'); err != nil {
+				return nil, err
 			}
-			lexer.tokens.Add(Token{Type: IMPLIES, Value: "->"})
-		case unicode.IsLetter(rune(char)):
-			lexer.tokens.Add(Token{Type: VAR, Value: string(char)})
+			lexer.addToken(IMPLIES, "->", start)
 		default:
-			return nil, fmt.Errorf("error when analyzing the char %s", string(char))
+			return nil, lexer.errorAt(start, start+size, fmt.Sprintf("error when analyzing the char %s", string(char)))
 		}
+	}
+
+	return lexer.tokens, nil
+}
 
+/*
+Return the next n bytes of the input, or less at the end of the input
+*/
+func (lexer *Lexer) peek(n int) string {
+	end := min(lexer.pos+n, len(lexer.input))
+	return lexer.input[lexer.pos:end]
+}
+
+/*
+Consume the expected characters of a multi-characters operator, or return
+an error pointing at the first character that does not match
+*/
+func (lexer *Lexer) expect(start int, operator string, chars ...byte) error {
+	for _, expected := range chars {
+		if lexer.pos >= len(lexer.input) {
+			return lexer.errorAt(start, lexer.pos, fmt.Sprintf("error when analyzing %s, found end of input, expected '%s'", operator, string(expected)))
+		}
+
+		if found, size := utf8.DecodeRuneInString(lexer.input[lexer.pos:]); found != rune(expected) {
+			return lexer.errorAt(lexer.pos, lexer.pos+size, fmt.Sprintf("error when analyzing %s, found '%s', expected '%s'", operator, string(found), string(expected)))
+		}
 		lexer.pos++
 	}
 
-	return lexer.tokens, nil
+	return nil
+}
+
+func (lexer *Lexer) addToken(tokenType TokenType, value string, start int) {
+	lexer.tokens.Add(Token{Type: tokenType, Value: value, Span: lexer.span(start, lexer.pos)})
+}
+
+/*
+Compute the span between two byte offsets of the current line
+*/
+func (lexer *Lexer) span(start, end int) Span {
+	return Span{Start: lexer.position(start), End: lexer.position(end)}
+}
+
+func (lexer *Lexer) position(offset int) Position {
+	if offset < lexer.lineStart {
+		offset = lexer.lineStart
+	}
+
+	if offset > len(lexer.input) {
+		offset = len(lexer.input)
+	}
+
+	column := utf8.RuneCountInString(lexer.input[lexer.lineStart:offset]) + 1
+	return Position{Offset: offset, Line: lexer.line, Column: column}
+}
+
+func (lexer *Lexer) errorAt(start, end int, message string) *ParseError {
+	return &ParseError{Message: message, Span: lexer.span(start, end)}
+}
+
+/*
+Read a whole identifier starting at the current position. Keywords are returned
+as operators, and the word "v" is read as an OR operator when it follows an
+operand (a v b), otherwise it is a variable
+*/
+func (lexer *Lexer) readIdentifier() Token {
+	start := lexer.pos
+	for lexer.pos < len(lexer.input) {
+		char, size := utf8.DecodeRuneInString(lexer.input[lexer.pos:])
+		if isIdentifierPart(char) {
+			lexer.pos += size
+			continue
+		}
+
+		if char == '.' && lexer.allowDottedVariables && lexer.pos+1 < len(lexer.input) {
+			if next, _ := utf8.DecodeRuneInString(lexer.input[lexer.pos+1:]); isIdentifierStart(next) {
+				lexer.pos++
+				continue
+			}
+		}
+
+		break
+	}
+
+	word := lexer.input[start:lexer.pos]
+	if keyword, ok := keywords[strings.ToLower(word)]; ok {
+		return keyword
+	}
+
+	if word == "v" && lexer.lastTokenEndsOperand() {
+		return Token{Type: OR, Value: "OR"}
+	}
+
+	return Token{Type: VAR, Value: word}
+}
+
+/*
+Return true if the last token read closes an operand (variable, number or right parenthesis)
+*/
+func (lexer *Lexer) lastTokenEndsOperand() bool {
+	if lexer.tokens.IsEmpty() {
+		return false
+	}
+
+	last, _ := lexer.tokens.At(lexer.tokens.Size() - 1)
+	return last.Is(VAR) || last.Is(NUMBER) || last.Is(RPAREN)
 }
 
 // Private functions
-func isAndOperator(char byte) bool {
-	return char == '&' || char == '.' || char == '^'
+func isIdentifierStart(char rune) bool {
+	return unicode.IsLetter(char) || char == '_'
 }
 
-func isOrOperator(char byte) bool {
-	return char == '|' || char == 'v'
+func isIdentifierPart(char rune) bool {
+	return isIdentifierStart(char) || unicode.IsDigit(char)
 }
 
-func isNotOperator(char byte) bool {
-	return char == '!'
+func isAndOperator(char rune) bool {
+	return char == '&' || char == '.' || char == '^' || char == '∧'
 }
 
-func isXOROperator(char byte) bool {
-	return char == '+'
+func isOrOperator(char rune) bool {
+	return char == '|' || char == '∨'
 }
 
-func isNumber(char byte) bool {
-	return char == '0' || char == '1'
+func isNotOperator(char rune) bool {
+	return char == '!' || char == '~' || char == '¬'
+}
+
+func isXOROperator(char rune) bool {
+	return char == '+' || char == '⊕' || char == '⊻'
+}
+
+func isImpliesOperator(char rune) bool {
+	return char == '→' || char == '⇒'
+}
+
+func isEquivalenceOperator(char rune) bool {
+	return char == '↔' || char == '⇔'
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isTrue(char rune) bool {
+	return char == '⊤'
+}
+
+func isFalse(char rune) bool {
+	return char == '⊥'
+}
+
+/*
+Return a readable name of the token type, used in error messages
+*/
+func (tokenType TokenType) String() string {
+	switch tokenType {
+	case EOF:
+		return "end of input"
+	case VAR:
+		return "variable"
+	case AND:
+		return "AND operator"
+	case OR:
+		return "OR operator"
+	case XOR:
+		return "XOR operator"
+	case NOT:
+		return "NOT operator"
+	case LPAREN:
+		return "'('"
+	case RPAREN:
+		return "')'"
+	case IMPLIES:
+		return "'->'"
+	case NUMBER:
+		return "number"
+	case EQUIVALENCE:
+		return "'<->'"
+	case EQUAL:
+		return "'=='"
+	case NOT_EQUAL:
+		return "'!='"
+	case COMMA:
+		return "','"
+	case GREATER_EQUAL:
+		return "'>='"
+	case LESS_EQUAL:
+		return "'<='"
+	default:
+		return "illegal token"
+	}
 }
 
 /*
@@ -154,6 +405,15 @@ func (token Token) Is(tokenType TokenType) bool {
 	return token.Type == tokenType
 }
 
+/*
+Return true if the token is an integer written with digits. The constants ⊤, ⊥, true and
+false are NUMBER tokens too, but their text is longer than their value 1 or 0
+*/
+func (token Token) isInteger() bool {
+	return token.Is(NUMBER) && token.Span.End.Offset-token.Span.Start.Offset == len(token.Value)
+}
+
 func (token Token) IsOperator() bool {
-	return token.Is(OR) || token.Is(AND) || token.Is(XOR) || token.Is(IMPLIES) || token.Is(EQUIVALENCE)
+	return token.Is(OR) || token.Is(AND) || token.Is(XOR) || token.Is(IMPLIES) || token.Is(EQUIVALENCE) ||
+		token.Is(EQUAL) || token.Is(NOT_EQUAL)
 }
@@ -36,7 +36,7 @@ func TestTokenize(t *testing.T) {
 		{"test with an AND expression with .", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: AND, Value: "AND"}, Token{Type: VAR, Value: "b"}), "a.b", false},
 		{"test with an AND expression with ^", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: AND, Value: "AND"}, Token{Type: VAR, Value: "b"}), "a^b", false},
 		{"test with an OR expression with |", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: OR, Value: "OR"}, Token{Type: VAR, Value: "b"}), "a|b", false},
-		{"test with an OR expression with v", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: OR, Value: "OR"}, Token{Type: VAR, Value: "b"}), "avb", false},
+		{"test with an OR expression with v", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: OR, Value: "OR"}, Token{Type: VAR, Value: "b"}), "a v b", false},
 		{"test with an NOT expression", arraylist.New(mockTokenCompare, Token{Type: NOT, Value: "NOT"}, Token{Type: VAR, Value: "a"}), "!a", false},
 		{"test with parenthesis", arraylist.New(mockTokenCompare, Token{Type: LPAREN, Value: "("}, Token{Type: VAR, Value: "a"}, Token{Type: AND, Value: "AND"}, Token{Type: VAR, Value: "b"}, Token{Type: RPAREN, Value: ")"}), "(a&b)", false},
 		{"test with an AND expression", arraylist.New(mockTokenCompare, Token{Type: NOT, Value: "NOT"}, Token{Type: VAR, Value: "a"}), "!a", false},
@@ -48,6 +48,12 @@ func TestTokenize(t *testing.T) {
 		{"test with numbers", arraylist.New(mockTokenCompare, Token{Type: NUMBER, Value: "1"}, Token{Type: XOR, Value: "XOR"}, Token{Type: NUMBER, Value: "1"}), " 1+1 ", false},
 		{"test bad equivalence operator (without -)", arraylist.New(mockTokenCompare), "a<b", true},
 		{"test bad equivalence operator (without >)", arraylist.New(mockTokenCompare), "a<-b", true},
+		{"test multi-character variables", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "enable"}, Token{Type: AND, Value: "AND"}, Token{Type: VAR, Value: "reset_n"}, Token{Type: OR, Value: "OR"}, Token{Type: VAR, Value: "x12"}), "enable & reset_n | x12", false},
+		{"test v as a variable", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "v"}, Token{Type: OR, Value: "OR"}, Token{Type: VAR, Value: "v"}), "v v v", false},
+		{"test identifier containing v", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "avb"}), "avb", false},
+		{"test comparisons", arraylist.New(mockTokenCompare, Token{Type: NUMBER, Value: "12"}, Token{Type: VAR, Value: "a"}, Token{Type: GREATER_EQUAL, Value: ">="}, Token{Type: NUMBER, Value: "3"}, Token{Type: LESS_EQUAL, Value: "<="}), "12a >= 3 <=", false},
+		{"test unicode comparisons", arraylist.New(mockTokenCompare, Token{Type: GREATER_EQUAL, Value: ">="}, Token{Type: LESS_EQUAL, Value: "<="}), "≥ ≤", false},
+		{"test commas", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "atmost"}, Token{Type: LPAREN, Value: "("}, Token{Type: NUMBER, Value: "1"}, Token{Type: COMMA, Value: ","}, Token{Type: VAR, Value: "a"}, Token{Type: RPAREN, Value: ")"}), "atmost(1,a)", false},
 		{"tes equivalence operator", arraylist.New(mockTokenCompare, Token{Type: VAR, Value: "a"}, Token{Type: EQUIVALENCE, Value: "<->"}, Token{Type: VAR, Value: "a"}), "a<->a", false},
 	}
 
@@ -66,6 +72,76 @@ func TestTokenize(t *testing.T) {
 	}
 }
 
+func TestTokenizeDottedVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens, err := NewLexer("user.isAdmin & a.b").WithDottedVariables().Tokenize()
+	assert.Nil(err)
+	assert.Equal(3, tokens.Size())
+	assert.True(tokens.Contains(Token{Type: VAR, Value: "user.isAdmin"}))
+	assert.True(tokens.Contains(Token{Type: VAR, Value: "a.b"}))
+
+	tokens, err = NewLexer("a.b").Tokenize()
+	assert.Nil(err)
+	assert.True(tokens.Contains(Token{Type: AND, Value: "AND"}))
+}
+
+func TestTokenizeAlternativeSyntax(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name   string
+		input  string
+		tokens []Token
+	}{
+		{"test unicode and", "a ∧ b", []Token{{Type: VAR, Value: "a"}, {Type: AND, Value: "AND"}, {Type: VAR, Value: "b"}}},
+		{"test unicode or", "a∨b", []Token{{Type: VAR, Value: "a"}, {Type: OR, Value: "OR"}, {Type: VAR, Value: "b"}}},
+		{"test unicode not", "¬a", []Token{{Type: NOT, Value: "NOT"}, {Type: VAR, Value: "a"}}},
+		{"test tilde not", "~a", []Token{{Type: NOT, Value: "NOT"}, {Type: VAR, Value: "a"}}},
+		{"test unicode xor", "a⊕b", []Token{{Type: VAR, Value: "a"}, {Type: XOR, Value: "XOR"}, {Type: VAR, Value: "b"}}},
+		{"test unicode implies", "a → b ⇒ c", []Token{{Type: VAR, Value: "a"}, {Type: IMPLIES, Value: "->"}, {Type: VAR, Value: "b"}, {Type: IMPLIES, Value: "->"}, {Type: VAR, Value: "c"}}},
+		{"test unicode equivalence", "a↔b⇔c", []Token{{Type: VAR, Value: "a"}, {Type: EQUIVALENCE, Value: "<->"}, {Type: VAR, Value: "b"}, {Type: EQUIVALENCE, Value: "<->"}, {Type: VAR, Value: "c"}}},
+		{"test unicode constants", "⊤⊥", []Token{{Type: NUMBER, Value: "1"}, {Type: NUMBER, Value: "0"}}},
+		{"test keywords", "not a AND b Or c xor d implies e IFF f", []Token{{Type: NOT, Value: "NOT"}, {Type: VAR, Value: "a"}, {Type: AND, Value: "AND"}, {Type: VAR, Value: "b"}, {Type: OR, Value: "OR"}, {Type: VAR, Value: "c"}, {Type: XOR, Value: "XOR"}, {Type: VAR, Value: "d"}, {Type: IMPLIES, Value: "->"}, {Type: VAR, Value: "e"}, {Type: EQUIVALENCE, Value: "<->"}, {Type: VAR, Value: "f"}}},
+		{"test boolean keywords", "TRUE false", []Token{{Type: NUMBER, Value: "1"}, {Type: NUMBER, Value: "0"}}},
+		{"test C-style operators", "a && b || c == d != e", []Token{{Type: VAR, Value: "a"}, {Type: AND, Value: "AND"}, {Type: VAR, Value: "b"}, {Type: OR, Value: "OR"}, {Type: VAR, Value: "c"}, {Type: EQUAL, Value: "=="}, {Type: VAR, Value: "d"}, {Type: NOT_EQUAL, Value: "!="}, {Type: VAR, Value: "e"}}},
+		{"test unicode variables", "été ∧ x1", []Token{{Type: VAR, Value: "été"}, {Type: AND, Value: "AND"}, {Type: VAR, Value: "x1"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, err := NewLexer(test.input).Tokenize()
+			assert.Nil(err, test.name)
+			assert.Equal(len(test.tokens), tokens.Size(), test.name)
+			for index, expected := range test.tokens {
+				token, _ := tokens.At(index)
+				assert.Equal(expected.Type, token.Type, test.name)
+				assert.Equal(expected.Value, token.Value, test.name)
+			}
+		})
+	}
+}
+
+func TestTokenSpan(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens, err := NewLexer("a\n  ->  reset_n").Tokenize()
+	assert.Nil(err)
+
+	implies, _ := tokens.At(1)
+	assert.Equal(Span{Start: Position{Offset: 4, Line: 2, Column: 3}, End: Position{Offset: 6, Line: 2, Column: 5}}, implies.Span)
+
+	variable, _ := tokens.At(2)
+	assert.Equal(Span{Start: Position{Offset: 8, Line: 2, Column: 7}, End: Position{Offset: 15, Line: 2, Column: 14}}, variable.Span)
+
+	// columns are counted in characters, not in bytes
+	tokens, err = NewLexer("¬a ∧ b").Tokenize()
+	assert.Nil(err)
+
+	and, _ := tokens.At(2)
+	assert.Equal(Span{Start: Position{Offset: 4, Line: 1, Column: 4}, End: Position{Offset: 7, Line: 1, Column: 5}}, and.Span)
+}
+
 func TestString(t *testing.T) {
 	assert := assert.New(t)
 	token := Token{Type: AND, Value: "AND"}
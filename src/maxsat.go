@@ -0,0 +1,170 @@
+package logic
+
+import (
+	"fmt"
+)
+
+/*
+Optimization problem over expressions: the hard constraints must be satisfied, and the
+total weight of the soft constraints that are not satisfied, the cost, is minimized
+*/
+type MaxSAT struct {
+	hard    []Constraint
+	soft    []Constraint
+	weights []int
+}
+
+/*
+Result of a MaxSAT problem
+*/
+type MaxSATResult struct {
+	Assignment map[string]bool // Values of the variables of the constraints
+	Cost       int             // Total weight of the violated soft constraints
+	Violated   []string        // Names of the violated soft constraints
+}
+
+func NewMaxSAT() *MaxSAT {
+	return &MaxSAT{}
+}
+
+/*
+Add a constraint that must be satisfied
+*/
+func (problem *MaxSAT) AddHard(constraint Constraint) {
+	problem.hard = append(problem.hard, constraint)
+}
+
+/*
+Add a constraint that should be satisfied, its weight being added to the cost when it
+is not. The weight must be positive
+*/
+func (problem *MaxSAT) AddSoft(constraint Constraint, weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("the weight of the soft constraint %s must be positive, got %d", constraint.Name, weight)
+	}
+
+	problem.soft = append(problem.soft, constraint)
+	problem.weights = append(problem.weights, weight)
+	return nil
+}
+
+/*
+Return an assignment satisfying the hard constraints with a minimal cost, or false when
+the hard constraints are unsatisfiable.
+
+The optimum is found by a linear search from the cost of a first model: the weights of
+the violated soft constraints are summed by a generalized totalizer, and the sums
+reaching the cost of the best model found so far are forbidden, until the SAT solver
+can not find a better model
+*/
+func (problem *MaxSAT) Solve() (MaxSATResult, bool) {
+	encoder := newTseitinEncoder(problem.variables(), true)
+	for _, constraint := range problem.hard {
+		encoder.cnf.AddClause(encoder.literal(constraint.Expr, positivePolarity))
+	}
+	// a soft literal can only be true when its constraint is satisfied
+	violated := make([]Literal, len(problem.soft))
+	for index, constraint := range problem.soft {
+		violated[index] = encoder.literal(constraint.Expr, positivePolarity).Not()
+	}
+
+	cnf := encoder.cnf
+	solver := NewSATSolverFromCNF(cnf)
+	if !solver.Solve() {
+		return MaxSATResult{}, false
+	}
+
+	best := problem.resultOf(cnf.modelOf(solver))
+	if best.Cost == 0 {
+		return best, true
+	}
+
+	sums := newWeightedTotalizer(solver, violated, problem.weights, best.Cost)
+	for best.Cost > 0 {
+		sums.forbid(solver, best.Cost)
+		if !solver.Solve() {
+			break
+		}
+		best = problem.resultOf(cnf.modelOf(solver))
+	}
+	return best, true
+}
+
+/*
+Return the variables of the constraints, in their order of appearance
+*/
+func (problem *MaxSAT) variables() []string {
+	exprs := []Expression{}
+	for _, constraint := range append(append([]Constraint{}, problem.hard...), problem.soft...) {
+		exprs = append(exprs, constraint.Expr)
+	}
+	return variablesOf(exprs...)
+}
+
+/*
+Return the cost of an assignment, and the soft constraints it violates
+*/
+func (problem *MaxSAT) resultOf(assignment map[string]bool) MaxSATResult {
+	result := MaxSATResult{Assignment: assignment, Violated: []string{}}
+	for index, constraint := range problem.soft {
+		if !constraint.Expr.Eval(assignment) {
+			result.Cost += problem.weights[index]
+			result.Violated = append(result.Violated, constraint.Name)
+		}
+	}
+	return result
+}
+
+/*
+Generalized totalizer: a binary tree over weighted literals, where each node has an
+output literal for each sum of the weights of its true leaves, the output being true
+when the sum of the true leaves reaches it. The sums above the bound are merged into
+the output of the bound
+*/
+type weightedTotalizer struct {
+	outputs map[int]Literal // Output of each sum of the root
+}
+
+func newWeightedTotalizer(solver *SATSolver, literals []Literal, weights []int, bound int) *weightedTotalizer {
+	var build func(first, last int) map[int]Literal
+	build = func(first, last int) map[int]Literal {
+		if first == last {
+			return map[int]Literal{min(weights[first], bound): literals[first]}
+		}
+
+		middle := (first + last) / 2
+		left, right := build(first, middle), build(middle+1, last)
+		outputs := make(map[int]Literal)
+		output := func(sum int) Literal {
+			sum = min(sum, bound)
+			if _, ok := outputs[sum]; !ok {
+				outputs[sum] = solver.NewVariable()
+			}
+			return outputs[sum]
+		}
+
+		for sum, literal := range left {
+			solver.AddClause(literal.Not(), output(sum))
+		}
+		for sum, literal := range right {
+			solver.AddClause(literal.Not(), output(sum))
+			for leftSum, leftLiteral := range left {
+				solver.AddClause(leftLiteral.Not(), literal.Not(), output(leftSum+sum))
+			}
+		}
+		return outputs
+	}
+
+	return &weightedTotalizer{outputs: build(0, len(literals)-1)}
+}
+
+/*
+Forbid the sums reaching the bound
+*/
+func (totalizer *weightedTotalizer) forbid(solver *SATSolver, bound int) {
+	for sum, literal := range totalizer.outputs {
+		if sum >= bound {
+			solver.AddClause(literal.Not())
+		}
+	}
+}
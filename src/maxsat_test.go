@@ -0,0 +1,138 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return the minimal cost of the problem by walking the truth table, -1 when the hard
+constraints are unsatisfiable
+*/
+func bruteForceMaxSAT(hard []Expression, soft []Expression, weights []int) int {
+	variables := variablesOf(append(append([]Expression{}, hard...), soft...)...)
+	best := -1
+	for row := 0; row < 1<<len(variables); row++ {
+		assignment := assignmentOf(row, variables)
+		satisfied := true
+		for _, expr := range hard {
+			satisfied = satisfied && expr.Eval(assignment)
+		}
+		if !satisfied {
+			continue
+		}
+
+		cost := 0
+		for index, expr := range soft {
+			if !expr.Eval(assignment) {
+				cost += weights[index]
+			}
+		}
+		if best < 0 || cost < best {
+			best = cost
+		}
+	}
+	return best
+}
+
+func TestMaxSAT(t *testing.T) {
+	assert := assert.New(t)
+
+	type soft struct {
+		input  string
+		weight int
+	}
+	tests := []struct {
+		name             string
+		hard             []string
+		soft             []soft
+		expectedCost     int
+		expectedViolated []string
+	}{
+		{"test all satisfied", []string{"a | b"}, []soft{{"a", 1}, {"b", 1}}, 0, []string{}},
+		{"test conflict", []string{}, []soft{{"a", 2}, {"!a", 1}}, 1, []string{"!a"}},
+		{"test hard wins", []string{"!a"}, []soft{{"a", 10}, {"b", 1}}, 10, []string{"a"}},
+		{"test weights", []string{"a + b"}, []soft{{"a", 1}, {"b", 1}, {"a & c", 3}, {"!c", 1}}, 2, []string{"b", "!c"}},
+		{"test without soft", []string{"a -> b", "a"}, []soft{}, 0, []string{}},
+		{"test configurator", []string{"https -> ssl", "legacy -> !https"}, []soft{{"https", 5}, {"legacy", 3}, {"!ssl", 1}}, 4, []string{"legacy", "!ssl"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := NewMaxSAT()
+			for _, input := range test.hard {
+				expr, _ := parseInput(input)
+				problem.AddHard(Constraint{Name: input, Expr: expr})
+			}
+			for _, constraint := range test.soft {
+				expr, _ := parseInput(constraint.input)
+				assert.Nil(problem.AddSoft(Constraint{Name: constraint.input, Expr: expr}, constraint.weight))
+			}
+
+			result, ok := problem.Solve()
+			assert.True(ok)
+			assert.Equal(test.expectedCost, result.Cost)
+			assert.Equal(test.expectedViolated, result.Violated)
+			for _, constraint := range problem.hard {
+				assert.True(constraint.Expr.Eval(result.Assignment))
+			}
+		})
+	}
+}
+
+func TestMaxSATUnsatisfiableHardConstraints(t *testing.T) {
+	assert := assert.New(t)
+
+	problem := NewMaxSAT()
+	expr, _ := parseInput("a & !a")
+	problem.AddHard(Constraint{Name: "contradiction", Expr: expr})
+	expr, _ = parseInput("b")
+	assert.Nil(problem.AddSoft(Constraint{Name: "b", Expr: expr}, 1))
+
+	_, ok := problem.Solve()
+	assert.False(ok)
+}
+
+func TestMaxSATInvalidWeight(t *testing.T) {
+	assert := assert.New(t)
+
+	problem := NewMaxSAT()
+	err := problem.AddSoft(Constraint{Name: "a", Expr: NewVarExpression("a")}, 0)
+	assert.Equal("the weight of the soft constraint a must be positive, got 0", err.Error())
+}
+
+func TestMaxSATRandomProblems(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(27))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		problem := NewMaxSAT()
+		hard, soft, weights := []Expression{}, []Expression{}, []int{}
+		nbrHard, nbrSoft := random.Intn(3), 3+random.Intn(8)
+		for index := 0; index < nbrHard; index++ {
+			expr := randomExpression(random, 2, variables)
+			hard = append(hard, expr)
+			problem.AddHard(Constraint{Name: fmt.Sprintf("h%d", index), Expr: expr})
+		}
+		for index := 0; index < nbrSoft; index++ {
+			expr := randomExpression(random, 2, variables)
+			weight := 1 + random.Intn(5)
+			soft, weights = append(soft, expr), append(weights, weight)
+			assert.Nil(problem.AddSoft(Constraint{Name: fmt.Sprintf("s%d", index), Expr: expr}, weight))
+		}
+
+		expected := bruteForceMaxSAT(hard, soft, weights)
+		result, ok := problem.Solve()
+		assert.Equal(expected >= 0, ok)
+		if ok {
+			assert.Equal(expected, result.Cost)
+			for _, expr := range hard {
+				assert.True(expr.Eval(result.Assignment))
+			}
+		}
+	}
+}
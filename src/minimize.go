@@ -0,0 +1,435 @@
+package logic
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Maximal number of variables accepted by the exact minimizer
+const MAX_EXACT_VARIABLES = 16
+
+// Maximal number of rows and primes compared while searching a minimum cover
+const MAX_PETRICK_STEPS = 200000000
+
+/*
+Minimize an expression into a minimal sum of products. The prime implicants are
+computed with the Quine-McCluskey algorithm on the truth table of the expression,
+then a minimum cover is chosen with the Petrick's method. An error is returned
+when the minimum cover can not be found within MAX_PETRICK_STEPS steps
+*/
+func Minimize(expr Expression) (Expression, error) {
+	return MinimizeWithDontCares(expr, NewNumberExpression(0))
+}
+
+/*
+Minimize an expression into a minimal sum of products, where the rows for which
+dontCares is true can not occur: the result can take any value on these rows
+*/
+func MinimizeWithDontCares(expr, dontCares Expression) (Expression, error) {
+	variables := variablesOf(expr, dontCares)
+	if len(variables) > MAX_EXACT_VARIABLES {
+		return nil, fmt.Errorf("the exact minimizer supports at most %d variables, found %d", MAX_EXACT_VARIABLES, len(variables))
+	}
+
+	dontCareRows := mintermsOf(dontCares, variables)
+	isDontCare := make(map[int]bool, len(dontCareRows))
+	for _, row := range dontCareRows {
+		isDontCare[row] = true
+	}
+
+	minterms := []int{}
+	for _, row := range mintermsOf(expr, variables) {
+		if !isDontCare[row] {
+			minterms = append(minterms, row)
+		}
+	}
+
+	return MinimizeMinterms(variables, minterms, dontCareRows)
+}
+
+/*
+Minimize the function of the variables which is true on the minterms, can take any
+value on the don't care rows, and is false elsewhere. Rows are numbered as in the
+truth table: the variable at index k is true when the bit k of the row is set
+*/
+func MinimizeMinterms(variables []string, minterms, dontCares []int) (Expression, error) {
+	if len(variables) > MAX_EXACT_VARIABLES {
+		return nil, fmt.Errorf("the exact minimizer supports at most %d variables, found %d", MAX_EXACT_VARIABLES, len(variables))
+	}
+
+	for _, row := range append(append([]int{}, minterms...), dontCares...) {
+		if row < 0 || row >= 1<<len(variables) {
+			return nil, fmt.Errorf("the row %d does not exist with %d variables", row, len(variables))
+		}
+	}
+
+	cover, err := minimizeMinterms(minterms, dontCares, len(variables))
+	if err != nil {
+		return nil, err
+	}
+	return coverToExpression(cover, variables), nil
+}
+
+/*
+Return a minimum cover of the minterms, using the don't care rows to build larger implicants
+*/
+func minimizeMinterms(minterms, dontCares []int, nbrVariables int) ([]cube, error) {
+	if len(minterms) == 0 {
+		return []cube{}, nil
+	}
+
+	primes := primeImplicants(append(append([]int{}, minterms...), dontCares...), nbrVariables)
+	return minimumCover(primes, minterms)
+}
+
+/*
+Compute all the prime implicants of a set of minterms with the Quine-McCluskey
+algorithm: cubes differing by a single literal are merged until no merge is possible
+*/
+func primeImplicants(minterms []int, nbrVariables int) []cube {
+	current := make(map[cube]bool)
+	for _, minterm := range minterms {
+		current[mintermCube(minterm, nbrVariables)] = true
+	}
+
+	primes := []cube{}
+	for len(current) > 0 {
+		next := make(map[cube]bool)
+		merged := make(map[cube]bool)
+
+		for c := range current {
+			// each pair is merged once, from its cube with the negative literal
+			for remaining := c.mask &^ c.value; remaining != 0; remaining &= remaining - 1 {
+				bit := remaining & -remaining
+				partner := cube{value: c.value | bit, mask: c.mask}
+				if current[partner] {
+					merged[c] = true
+					merged[partner] = true
+					next[cube{value: c.value, mask: c.mask &^ bit}] = true
+				}
+			}
+		}
+
+		for c := range current {
+			if !merged[c] {
+				primes = append(primes, c)
+			}
+		}
+		current = next
+	}
+
+	sortCubes(primes)
+	return primes
+}
+
+/*
+Choose a minimum set of prime implicants covering all the minterms, preferring the
+solutions with the fewest literals. Each minterm gives a sum of the primes covering
+it, and the product of these sums is the Petrick's expression, solved with a branch
+and bound instead of being expanded
+*/
+func minimumCover(primes []cube, minterms []int) ([]cube, error) {
+	words := (len(primes) + 63) / 64
+	rows := []indexSet{}
+	for _, minterm := range minterms {
+		row := make(indexSet, words)
+		for index, prime := range primes {
+			if prime.containsMinterm(uint64(minterm)) {
+				row.add(index)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	solver := petrickSolver{primes: primes}
+	solver.search(rows, make(indexSet, words))
+	if solver.steps > MAX_PETRICK_STEPS {
+		return nil, fmt.Errorf("no minimum cover found within %d steps, the heuristic minimizer can be used instead", MAX_PETRICK_STEPS)
+	}
+
+	cover := []cube{}
+	for _, index := range solver.best.indexes() {
+		cover = append(cover, primes[index])
+	}
+	return cover, nil
+}
+
+/*
+Branch and bound search of the cheapest product of the Petrick's expression.
+Each row is the set of primes covering a minterm
+*/
+type petrickSolver struct {
+	primes                     []cube
+	best                       indexSet
+	bestProducts, bestLiterals int
+	steps                      int
+}
+
+func (solver *petrickSolver) search(rows []indexSet, selected indexSet) {
+	if solver.steps > MAX_PETRICK_STEPS {
+		return
+	}
+
+	rows, essentials := solver.reduce(rows)
+	selected = selected.union(essentials)
+	products, literals := selected.cost(solver.primes)
+
+	if len(rows) == 0 {
+		if solver.best == nil || products < solver.bestProducts || (products == solver.bestProducts && literals < solver.bestLiterals) {
+			solver.best, solver.bestProducts, solver.bestLiterals = selected, products, literals
+		}
+		return
+	}
+
+	if solver.best != nil {
+		boundProducts, boundLiterals := solver.lowerBound(rows)
+		products, literals = products+boundProducts, literals+boundLiterals
+		if products > solver.bestProducts || (products == solver.bestProducts && literals >= solver.bestLiterals) {
+			return
+		}
+	}
+
+	// branch on the primes of the row with the fewest choices
+	branch := rows[0]
+	for _, row := range rows {
+		if row.size() < branch.size() {
+			branch = row
+		}
+	}
+
+	for _, index := range solver.byCoverage(branch.indexes(), rows) {
+		remaining := []indexSet{}
+		for _, row := range rows {
+			if !row.has(index) {
+				remaining = append(remaining, row)
+			}
+		}
+		solver.search(remaining, selected.with(index))
+	}
+}
+
+/*
+Simplify the rows until nothing changes: select the primes that are the only choice
+of a row, remove the rows containing another row, and remove the primes covering
+fewer rows than another prime with no more literals
+*/
+func (solver *petrickSolver) reduce(rows []indexSet) ([]indexSet, indexSet) {
+	essentials := make(indexSet, (len(solver.primes)+63)/64)
+	for changed := true; changed && len(rows) > 0; {
+		changed = false
+
+		for _, row := range rows {
+			if row.size() == 1 {
+				essentials = essentials.union(row)
+			}
+		}
+
+		kept := []indexSet{}
+		for index, row := range rows {
+			if row.intersects(essentials) || solver.isDominatedRow(rows, index) {
+				changed = true
+			} else {
+				kept = append(kept, row)
+			}
+		}
+		rows = kept
+
+		solver.steps += len(rows) * len(rows)
+		if dominated := solver.dominatedColumns(rows); dominated.size() > 0 {
+			for index, row := range rows {
+				rows[index] = row.without(dominated)
+			}
+			changed = true
+		}
+	}
+	return rows, essentials
+}
+
+/*
+Return true if another row is contained in the row, covering it for free.
+Among identical rows, only the first one is kept
+*/
+func (solver *petrickSolver) isDominatedRow(rows []indexSet, index int) bool {
+	for other, row := range rows {
+		if other != index && row.isSubsetOf(rows[index]) && (other < index || !rows[index].isSubsetOf(row)) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Return the primes whose rows are all covered by a prime with no more literals.
+Among equivalent primes, only the first one is kept
+*/
+func (solver *petrickSolver) dominatedColumns(rows []indexSet) indexSet {
+	present := make(indexSet, (len(solver.primes)+63)/64)
+	for _, row := range rows {
+		present = present.union(row)
+	}
+
+	indexes := present.indexes()
+	solver.steps += len(indexes) * len(indexes)
+	words := (len(rows) + 63) / 64
+	columns := make([]indexSet, len(indexes))
+	for position, index := range indexes {
+		columns[position] = make(indexSet, words)
+		for rowIndex, row := range rows {
+			if row.has(index) {
+				columns[position].add(rowIndex)
+			}
+		}
+	}
+
+	dominated := make(indexSet, (len(solver.primes)+63)/64)
+	for position, index := range indexes {
+		for otherPosition, other := range indexes {
+			if other == index || dominated.has(other) || !columns[position].isSubsetOf(columns[otherPosition]) {
+				continue
+			}
+
+			literals, otherLiterals := solver.primes[index].literals(), solver.primes[other].literals()
+			if otherLiterals > literals {
+				continue
+			}
+
+			equivalent := otherLiterals == literals && columns[otherPosition].isSubsetOf(columns[position])
+			if !equivalent || other < index {
+				dominated.add(index)
+				break
+			}
+		}
+	}
+	return dominated
+}
+
+/*
+Return a lower bound of the cost needed to cover the rows: rows sharing no prime
+need one prime each
+*/
+func (solver *petrickSolver) lowerBound(rows []indexSet) (int, int) {
+	// the rows with few primes are the least likely to share one
+	sorted := append([]indexSet{}, rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].size() < sorted[j].size()
+	})
+
+	used := make(indexSet, (len(solver.primes)+63)/64)
+	products, literals := 0, 0
+	for _, row := range sorted {
+		if row.intersects(used) {
+			continue
+		}
+
+		used = used.union(row)
+		cheapest := -1
+		for _, index := range row.indexes() {
+			if cheapest < 0 || solver.primes[index].literals() < cheapest {
+				cheapest = solver.primes[index].literals()
+			}
+		}
+		products, literals = products+1, literals+cheapest
+	}
+	return products, literals
+}
+
+/*
+Sort the primes so that the ones covering the most rows, then with the fewest
+literals, are tried first
+*/
+func (solver *petrickSolver) byCoverage(indexes []int, rows []indexSet) []int {
+	coverage := make(map[int]int)
+	for _, row := range rows {
+		for _, index := range indexes {
+			if row.has(index) {
+				coverage[index]++
+			}
+		}
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		if coverage[indexes[i]] != coverage[indexes[j]] {
+			return coverage[indexes[i]] > coverage[indexes[j]]
+		}
+		return solver.primes[indexes[i]].literals() < solver.primes[indexes[j]].literals()
+	})
+	return indexes
+}
+
+// Set of indexes, one bit per index
+type indexSet []uint64
+
+func (set indexSet) add(index int) {
+	set[index/64] |= 1 << (index % 64)
+}
+
+func (set indexSet) has(index int) bool {
+	return set[index/64]&(1<<(index%64)) != 0
+}
+
+func (set indexSet) with(index int) indexSet {
+	copied := append(indexSet{}, set...)
+	copied.add(index)
+	return copied
+}
+
+func (set indexSet) union(other indexSet) indexSet {
+	union := append(indexSet{}, set...)
+	for word := range union {
+		union[word] |= other[word]
+	}
+	return union
+}
+
+func (set indexSet) without(other indexSet) indexSet {
+	difference := append(indexSet{}, set...)
+	for word := range difference {
+		difference[word] &^= other[word]
+	}
+	return difference
+}
+
+func (set indexSet) intersects(other indexSet) bool {
+	for word := range set {
+		if set[word]&other[word] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (set indexSet) isSubsetOf(other indexSet) bool {
+	for word := range set {
+		if set[word]&^other[word] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (set indexSet) size() int {
+	size := 0
+	for _, word := range set {
+		size += bits.OnesCount64(word)
+	}
+	return size
+}
+
+func (set indexSet) indexes() []int {
+	indexes := []int{}
+	for word, value := range set {
+		for ; value != 0; value &= value - 1 {
+			indexes = append(indexes, word*64+bits.TrailingZeros64(value))
+		}
+	}
+	return indexes
+}
+
+func (set indexSet) cost(primes []cube) (int, int) {
+	literals := 0
+	for _, index := range set.indexes() {
+		literals += primes[index].literals()
+	}
+	return set.size(), literals
+}
@@ -0,0 +1,252 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return true if the two expressions have the same truth table
+*/
+func sameTruthTable(first, second Expression) bool {
+	variables := variablesOf(first, second)
+	for row := 0; row < 1<<len(variables); row++ {
+		assignment := assignmentOf(row, variables)
+		if first.Eval(assignment) != second.Eval(assignment) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return the number of products and literals of a sum of products
+*/
+func sumOfProductsCost(expr Expression) (int, int) {
+	if value, ok := expr.(*OrExpression); ok {
+		leftProducts, leftLiterals := sumOfProductsCost(value.left)
+		rightProducts, rightLiterals := sumOfProductsCost(value.right)
+		return leftProducts + rightProducts, leftLiterals + rightLiterals
+	}
+
+	return 1, len(Variables(expr))
+}
+
+func TestMinimize(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test adjacent products", "a&b | a&!b", "a"},
+		{"test consensus", "a&b | !a&c | b&c", "a & b | !a & c"},
+		{"test tautology", "a | !a", "1"},
+		{"test contradiction", "a & !a", "0"},
+		{"test xor can not be reduced", "a + b", "a & !b | !a & b"},
+		{"test implies", "a -> b", "!a | b"},
+		{"test absorption on the right", "(a & b) | a", "a"},
+		{"test majority", "a&b | a&c | b&c | a&b&c", "a & b | a & c | b & c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			minimized, err := Minimize(expr)
+			assert.Nil(err)
+			assert.Equal(test.expected, minimized.String(), test.name)
+		})
+	}
+}
+
+func TestMinimizeCyclicCore(t *testing.T) {
+	assert := assert.New(t)
+
+	// Σm(0,1,2,5,6,7) has no essential prime implicant, 2 minimal covers of 3 products
+	expr, _ := parseInput("!a&!b&!c | a&!b&!c | !a&b&!c | a&!b&c | !a&b&c | a&b&c")
+	minimized, err := Minimize(expr)
+	assert.Nil(err)
+	assert.True(sameTruthTable(expr, minimized))
+
+	products, literals := sumOfProductsCost(minimized)
+	assert.Equal(3, products)
+	assert.Equal(6, literals)
+}
+
+func TestMinimizeRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(5))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(random, 5, variables)
+		minimized, err := Minimize(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, minimized), expr.String())
+	}
+}
+
+func TestMinimizeTooManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	var expr Expression = NewVarExpression("x0")
+	for i := 1; i <= MAX_EXACT_VARIABLES; i++ {
+		expr = NewAndExpression(expr, NewVarExpression(string(rune('a'+i))))
+	}
+
+	_, err := Minimize(expr)
+	assert.NotNil(err)
+}
+
+func TestPrimeImplicants(t *testing.T) {
+	assert := assert.New(t)
+
+	// f(a, b, c) = Σm(0,1,2,5,6,7), a is the bit 0
+	primes := primeImplicants([]int{0, 1, 2, 5, 6, 7}, 3)
+	assert.Equal(6, len(primes))
+	for _, prime := range primes {
+		assert.Equal(2, prime.literals())
+	}
+}
+
+func TestMinimumCoverIsMinimum(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(8))
+
+	for i := 0; i < 100; i++ {
+		minterms := []int{}
+		for row := 0; row < 16; row++ {
+			if random.Intn(2) == 0 {
+				minterms = append(minterms, row)
+			}
+		}
+
+		primes := primeImplicants(minterms, 4)
+		if len(minterms) == 0 || len(primes) > 16 {
+			continue
+		}
+
+		// try every subset of primes
+		bestProducts, bestLiterals := len(primes)+1, 0
+		for subset := 0; subset < 1<<len(primes); subset++ {
+			cover := []cube{}
+			for index, prime := range primes {
+				if subset&(1<<index) != 0 {
+					cover = append(cover, prime)
+				}
+			}
+
+			covered := true
+			for _, minterm := range minterms {
+				covered = covered && intersectsCover(mintermCube(minterm, 4), cover)
+			}
+			products, literals := coverCost(cover)
+			if covered && (products < bestProducts || (products == bestProducts && literals < bestLiterals)) {
+				bestProducts, bestLiterals = products, literals
+			}
+		}
+
+		cover, err := minimumCover(primes, minterms)
+		assert.Nil(err)
+		products, literals := coverCost(cover)
+		assert.Equal(bestProducts, products)
+		assert.Equal(bestLiterals, literals)
+	}
+}
+
+func TestMinimizeRandomMinterms(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(2))
+	variables := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	// half of the 256 rows are true, the hardest case for the exact minimizer
+	var expr Expression = NewNumberExpression(0)
+	for row := 0; row < 1<<len(variables); row++ {
+		if random.Intn(2) == 0 {
+			expr = NewOrExpression(expr, mintermCube(row, len(variables)).toExpression(variables))
+		}
+	}
+
+	minimized, err := Minimize(expr)
+	assert.Nil(err)
+	assert.True(sameTruthTable(expr, minimized))
+
+	heuristic, _ := MinimizeHeuristic(expr, EspressoExhaustive)
+	exactProducts, _ := sumOfProductsCost(minimized)
+	heuristicProducts, _ := sumOfProductsCost(heuristic)
+	assert.LessOrEqual(exactProducts, heuristicProducts)
+}
+
+func TestMinimizeWithDontCares(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name      string
+		input     string
+		dontCares string
+		expected  string
+	}{
+		{"test xor with the both row", "a&!b | !a&b", "a&b", "a | b"},
+		{"test don't care on an unknown variable", "a&c", "a&!c", "a"},
+		{"test only don't cares", "a&b", "a&b", "0"},
+		{"test don't cares that do not help", "d | c&b | c&a", "d&c | d&b", "d | c & b | c & a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+			dontCares, err := parseInput(test.dontCares)
+			assert.Nil(err)
+
+			minimized, err := MinimizeWithDontCares(expr, dontCares)
+			assert.Nil(err)
+			assert.Equal(test.expected, minimized.String(), test.name)
+		})
+	}
+}
+
+func TestMinimizeWithDontCaresRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(12))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(random, 4, variables)
+		dontCares := randomExpression(random, 3, variables)
+		minimized, err := MinimizeWithDontCares(expr, dontCares)
+		assert.Nil(err)
+
+		all := variablesOf(expr, dontCares, minimized)
+		for row := 0; row < 1<<len(all); row++ {
+			assignment := assignmentOf(row, all)
+			if !dontCares.Eval(assignment) {
+				assert.Equal(expr.Eval(assignment), minimized.Eval(assignment), expr.String())
+			}
+		}
+
+		exact, _ := Minimize(expr)
+		withDontCares, _ := sumOfProductsCost(minimized)
+		withoutDontCares, _ := sumOfProductsCost(exact)
+		if exact.String() != "0" && minimized.String() != "0" {
+			assert.LessOrEqual(withDontCares, withoutDontCares)
+		}
+	}
+}
+
+func TestMinimizeMinterms(t *testing.T) {
+	assert := assert.New(t)
+
+	// Σm(1,3,7) + d(5), a is the bit 0
+	minimized, err := MinimizeMinterms([]string{"a", "b", "c"}, []int{1, 3, 7}, []int{5})
+	assert.Nil(err)
+	assert.Equal("a", minimized.String())
+
+	_, err = MinimizeMinterms([]string{"a"}, []int{2}, nil)
+	assert.NotNil(err)
+}
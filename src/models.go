@@ -0,0 +1,93 @@
+package logic
+
+/*
+Options of the model enumeration
+*/
+type ModelOptions struct {
+	Projection []string // Variables of the models, all the variables of the expression when empty
+	Limit      int      // Maximal number of models, no limit when 0
+}
+
+/*
+Iterator over the models of an expression. Each model found by the SAT solver is
+blocked by a clause before searching the next one, so the models are produced one
+by one without walking the truth table
+*/
+type ModelIterator struct {
+	solver    *SATSolver
+	variables []string
+	literals  []Literal
+	count     int
+	limit     int
+	done      bool
+}
+
+/*
+Return an iterator over the models of the expression. With a projection, the models
+only give the values of the projected variables, and each of them is produced once
+even if it extends to several models of the expression
+*/
+func Models(expr Expression, options ModelOptions) *ModelIterator {
+	cnf := PlaistedGreenbaumCNF(expr)
+
+	variables := options.Projection
+	if len(variables) == 0 {
+		variables = cnf.Variables[:cnf.NbrInputs]
+	}
+
+	// the projected variables missing from the expression take both values
+	literals := make([]Literal, len(variables))
+	for index, variable := range variables {
+		literals[index] = cnf.Literal(variable)
+	}
+
+	return &ModelIterator{
+		solver:    NewSATSolverFromCNF(cnf),
+		variables: variables,
+		literals:  literals,
+		limit:     options.Limit,
+	}
+}
+
+/*
+Return the next model, or false when all the models (or the limit) have been produced
+*/
+func (iterator *ModelIterator) Next() (map[string]bool, bool) {
+	if iterator.done || (iterator.limit > 0 && iterator.count >= iterator.limit) || !iterator.solver.Solve() {
+		iterator.done = true
+		return nil, false
+	}
+
+	model := make(map[string]bool, len(iterator.variables))
+	blocking := make([]Literal, len(iterator.literals))
+	for index, literal := range iterator.literals {
+		model[iterator.variables[index]] = iterator.solver.Value(literal)
+		blocking[index] = literal
+		if iterator.solver.Value(literal) {
+			blocking[index] = literal.Not()
+		}
+	}
+
+	iterator.solver.AddClause(blocking...)
+	iterator.count++
+	return model, true
+}
+
+/*
+Return the variables of the models, in the order of the projection
+*/
+func (iterator *ModelIterator) Variables() []string {
+	return iterator.variables
+}
+
+/*
+Return all the models of the expression, see Models
+*/
+func AllModels(expr Expression, options ModelOptions) []map[string]bool {
+	models := []map[string]bool{}
+	iterator := Models(expr, options)
+	for model, ok := iterator.Next(); ok; model, ok = iterator.Next() {
+		models = append(models, model)
+	}
+	return models
+}
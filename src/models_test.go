@@ -0,0 +1,116 @@
+package logic
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return the rows of the models, numbered as by assignmentOf
+*/
+func rowsOfModels(models []map[string]bool, variables []string) []int {
+	rows := []int{}
+	for _, model := range models {
+		row := 0
+		for index, variable := range variables {
+			if model[variable] {
+				row |= 1 << index
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+func TestAllModels(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{"test and", "a & b", []int{3}},
+		{"test or", "a | b", []int{1, 2, 3}},
+		{"test xor", "a + b", []int{1, 2}},
+		{"test contradiction", "a & !a", []int{}},
+		{"test tautology", "a | !a", []int{0, 1}},
+		{"test constant", "1", []int{0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			models := AllModels(expr, ModelOptions{})
+			assert.Equal(test.expected, rowsOfModels(models, Variables(expr)), test.name)
+		})
+	}
+}
+
+func TestAllModelsRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(16))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 5, variables)
+		models := AllModels(expr, ModelOptions{})
+		assert.Equal(mintermsOf(expr, Variables(expr)), rowsOfModels(models, Variables(expr)), Print(expr, ASCII))
+	}
+}
+
+func TestModelsProjection(t *testing.T) {
+	assert := assert.New(t)
+
+	// the values of c do not matter once projected on a and b
+	expr, _ := parseInput("(a | b) & (c | !c & a)")
+	models := AllModels(expr, ModelOptions{Projection: []string{"a", "b"}})
+	assert.Equal([]int{1, 2, 3}, rowsOfModels(models, []string{"a", "b"}))
+	for _, model := range models {
+		assert.Len(model, 2)
+	}
+
+	// a variable missing from the expression takes both values
+	expr, _ = parseInput("a")
+	models = AllModels(expr, ModelOptions{Projection: []string{"a", "z"}})
+	assert.Equal([]int{1, 3}, rowsOfModels(models, []string{"a", "z"}))
+}
+
+func TestModelsLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a | b | c")
+	iterator := Models(expr, ModelOptions{Limit: 3})
+	assert.Equal([]string{"a", "b", "c"}, iterator.Variables())
+
+	count := 0
+	for model, ok := iterator.Next(); ok; model, ok = iterator.Next() {
+		assert.True(expr.Eval(model))
+		count++
+	}
+	assert.Equal(3, count)
+
+	_, ok := iterator.Next()
+	assert.False(ok)
+}
+
+func TestModelsManyVariables(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(17))
+
+	// far too many rows for the truth table
+	expr := randomRuleSet(random, 60, 20, 3)
+	iterator := Models(expr, ModelOptions{Limit: 100})
+	seen := make(map[string]bool)
+	for model, ok := iterator.Next(); ok; model, ok = iterator.Next() {
+		assert.True(expr.Eval(model))
+		seen[formatAssignment(model, iterator.Variables())] = true
+	}
+	assert.Len(seen, 100)
+}
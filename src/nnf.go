@@ -0,0 +1,65 @@
+package logic
+
+/*
+Convert an expression into its negation normal form: implications, XOR and equivalences
+are rewritten with AND, OR and NOT, then the negations are pushed to the variables with
+the De Morgan's laws, so that NOT is only applied to variables. The operands of XOR and
+equivalences appear twice in the result, which can grow exponentially with their nesting.
+Cardinality and pseudo-Boolean constraints are expanded as decision trees
+*/
+func ToNNF(expr Expression) Expression {
+	return nnfOf(expr, false)
+}
+
+/*
+Return the negation normal form of the expression, or of its negation
+*/
+func nnfOf(expr Expression, negated bool) Expression {
+	switch value := expr.(type) {
+	case *VarExpression:
+		if negated {
+			return NewNotExpression(value)
+		}
+		return value
+	case *NumberExpression:
+		if negated {
+			return NewNumberExpression(1 - value.value)
+		}
+		return value
+	case *NotExpression:
+		return nnfOf(value.expr, !negated)
+	case *AndExpression:
+		return nnfJunction(value.left, value.right, negated, negated, negated)
+	case *OrExpression:
+		return nnfJunction(value.left, value.right, negated, negated, !negated)
+	case *ImpliesExpression:
+		// a -> b = !a | b
+		return nnfJunction(value.left, value.right, !negated, negated, !negated)
+	case *CardinalityExpression, *PseudoBooleanExpression:
+		return nnfOf(expandLinearConstraint(expr), negated)
+	}
+
+	// a + b = (a | b) & (!a | !b), a <-> b = (!a | b) & (a | !b)
+	children := operands(expr)
+	if _, isXOR := expr.(*XORExpression); isXOR != negated {
+		return NewAndExpression(
+			nnfJunction(children[0], children[1], false, false, true),
+			nnfJunction(children[0], children[1], true, true, true),
+		)
+	}
+	return NewAndExpression(
+		nnfJunction(children[0], children[1], true, false, true),
+		nnfJunction(children[0], children[1], false, true, true),
+	)
+}
+
+/*
+Return left OR right (disjunction) or left AND right in negation normal form, each
+operand being negated or not
+*/
+func nnfJunction(left, right Expression, negateLeft, negateRight, disjunction bool) Expression {
+	if disjunction {
+		return NewOrExpression(nnfOf(left, negateLeft), nnfOf(right, negateRight))
+	}
+	return NewAndExpression(nnfOf(left, negateLeft), nnfOf(right, negateRight))
+}
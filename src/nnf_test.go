@@ -0,0 +1,72 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return true if NOT is only applied to variables, and the only operators are AND and OR
+*/
+func isNNF(expr Expression) bool {
+	switch value := expr.(type) {
+	case *VarExpression, *NumberExpression:
+		return true
+	case *NotExpression:
+		_, ok := value.expr.(*VarExpression)
+		return ok
+	case *AndExpression, *OrExpression:
+		children := operands(expr)
+		return isNNF(children[0]) && isNNF(children[1])
+	default:
+		return false
+	}
+}
+
+func TestToNNF(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"test literal", "!a", "!a"},
+		{"test double negation", "!!a", "a"},
+		{"test de morgan", "!(a & (b | !c))", "!a | !b & c"},
+		{"test implies", "a -> b", "!a | b"},
+		{"test negated implies", "!(a -> b)", "a & !b"},
+		{"test xor", "a + b", "(a | b) & (!a | !b)"},
+		{"test equivalence", "a <-> b", "(!a | b) & (a | !b)"},
+		{"test negated equivalence", "!(a <-> b)", "(a | b) & (!a | !b)"},
+		{"test constant", "!(a & 0)", "!a | 1"},
+		{"test cardinality", "!atleast(1, a, b)", "!a & !b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			nnf := ToNNF(expr)
+			assert.Equal(test.expected, nnf.String(), test.name)
+			assert.True(isNNF(nnf), test.name)
+			assert.True(sameTruthTable(expr, nnf), test.name)
+		})
+	}
+}
+
+func TestToNNFRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(37))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 4, variables)
+		nnf := ToNNF(expr)
+		assert.True(isNNF(nnf), Print(expr, ASCII))
+		assert.True(sameTruthTable(expr, nnf), Print(expr, ASCII))
+	}
+}
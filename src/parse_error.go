@@ -0,0 +1,77 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Error returned by the Lexer and the Parser when the input is not a valid expression.
+It locates the offending part of the input so that it can be underlined
+*/
+type ParseError struct {
+	Message  string      // Description of the error
+	Span     Span        // Part of the input that caused the error
+	Found    Token       // Offending token, ILLEGAL when the error comes from the Lexer
+	Expected []TokenType // Tokens that would have been accepted at this position
+}
+
+/*
+Return the error prefixed by its line and column, followed by the expected tokens
+*/
+func (err *ParseError) Error() string {
+	message := err.Message
+	if len(err.Expected) > 0 {
+		message = fmt.Sprintf("%s, expected %s", message, joinTokenTypes(err.Expected))
+	}
+
+	return fmt.Sprintf("%d:%d: %s", err.Span.Start.Line, err.Span.Start.Column, message)
+}
+
+/*
+Render the line of the input containing the error, with carets under the offending span
+
+	a & (b | )
+	         ^
+*/
+func (err *ParseError) Snippet(input string) string {
+	lines := strings.Split(input, "\n")
+	lineIndex := err.Span.Start.Line - 1
+	if lineIndex < 0 || lineIndex >= len(lines) {
+		return ""
+	}
+
+	line := strings.TrimRight(lines[lineIndex], "\r")
+	width := 1
+	if err.Span.End.Line == err.Span.Start.Line && err.Span.End.Column > err.Span.Start.Column {
+		width = err.Span.End.Column - err.Span.Start.Column
+	}
+
+	// keep tabs in the padding so that the carets stay aligned
+	var padding strings.Builder
+	for index, char := range []rune(line) {
+		if index >= err.Span.Start.Column-1 {
+			break
+		}
+		if char == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+
+	return fmt.Sprintf("%s\n%s%s", line, padding.String(), strings.Repeat("^", width))
+}
+
+func joinTokenTypes(tokenTypes []TokenType) string {
+	names := make([]string, len(tokenTypes))
+	for index, tokenType := range tokenTypes {
+		names[index] = tokenType.String()
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	return fmt.Sprintf("%s or %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
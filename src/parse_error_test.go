@@ -0,0 +1,61 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseInput(input string) (Expression, error) {
+	tokens, err := NewLexer(input).Tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewParser(tokens).Parse()
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		line     int
+		column   int
+		expected []TokenType
+		snippet  string
+	}{
+		{"test missing operand", "a & (b | )", 1, 10, operandTokens, "a & (b | )\n         ^"},
+		{"test missing closing parenthesis", "(a & b", 1, 7, []TokenType{RPAREN}, "(a & b\n      ^"},
+		{"test unexpected variable", "abc def", 1, 5, binaryOperatorTokens, "abc def\n    ^^^"},
+		{"test error on second line", "a &\n  | b", 2, 3, operandTokens, "  | b\n  ^"},
+		{"test invalid char", "a = b", 1, 3, nil, "a = b\n  ^"},
+		{"test bad implies operator", "a -b", 1, 4, nil, "a -b\n   ^"},
+		{"test unfinished implies operator", "a -", 1, 3, nil, "a -\n  ^"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseInput(test.input)
+
+			var parseError *ParseError
+			assert.True(errors.As(err, &parseError), test.name)
+			assert.Equal(test.line, parseError.Span.Start.Line, test.name)
+			assert.Equal(test.column, parseError.Span.Start.Column, test.name)
+			assert.Equal(test.expected, parseError.Expected, test.name)
+			assert.Equal(test.snippet, parseError.Snippet(test.input), test.name)
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := parseInput("(a & b")
+	assert.Equal("1:7: missing closing parenthesis, found end of input, expected ')'", err.Error())
+
+	_, err = parseInput("a )")
+	assert.Equal("1:3: unexpected ')' after the expression, expected AND operator, OR operator, XOR operator, '->', '<->', '==', '!=' or end of input", err.Error())
+}
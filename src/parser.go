@@ -7,19 +7,35 @@ import (
 	"github.com/dterbah/gods/list"
 )
 
+// Tokens that can start an operand
+var operandTokens = []TokenType{VAR, NUMBER, LPAREN, NOT}
+
+// Tokens that can follow an operand
+var binaryOperatorTokens = []TokenType{AND, OR, XOR, IMPLIES, EQUIVALENCE, EQUAL, NOT_EQUAL}
+
 // Parser struct used to parse a boolean expression
 type Parser struct {
-	tokens list.List[Token]
-	pos    int
+	tokens      list.List[Token]
+	pos         int
+	plainSumEnd int // The terms before this position are not followed by a comparison
 }
 
 func NewParser(tokens list.List[Token]) *Parser {
 	return &Parser{tokens: tokens}
 }
 
-// Parse parses the entire expression
+// Parse parses the entire expression. The returned error is a *ParseError
 func (parser *Parser) Parse() (Expression, error) {
-	return parser.parseEquivalence()
+	expr, err := parser.parseEquivalence()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := parser.peekToken(); !token.Is(EOF) {
+		return nil, parser.errorAt(token, fmt.Sprintf("unexpected %s after the expression", describeToken(token)), append(binaryOperatorTokens, EOF)...)
+	}
+
+	return expr, nil
 }
 
 // parseEquivalence parses equivalence expressions (lowest priority)
@@ -100,14 +116,14 @@ func (parser *Parser) parseOr() (Expression, error) {
 
 // parseAnd parses AND expressions
 func (parser *Parser) parseAnd() (Expression, error) {
-	left, err := parser.parseNot()
+	left, err := parser.parseComparison()
 	if err != nil {
 		return nil, err
 	}
 
 	for parser.peekToken().Is(AND) {
 		parser.pos++
-		right, err := parser.parseNot()
+		right, err := parser.parseComparison()
 		if err != nil {
 			return nil, err
 		}
@@ -117,13 +133,43 @@ func (parser *Parser) parseAnd() (Expression, error) {
 	return left, nil
 }
 
+// parseComparison parses the C-style == and != operators, which bind tighter than AND
+// as in C and Go, so that a && b == c is read as a && (b == c)
+func (parser *Parser) parseComparison() (Expression, error) {
+	left, err := parser.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for token := parser.peekToken(); token.Is(EQUAL) || token.Is(NOT_EQUAL); token = parser.peekToken() {
+		parser.pos++
+		right, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Is(EQUAL) {
+			left = NewEquivalenceExpression(left, right)
+		} else {
+			left = NewXORExpression(left, right)
+		}
+	}
+
+	return left, nil
+}
+
 // parseNot parses NOT expressions
 func (parser *Parser) parseNot() (Expression, error) {
+	// the NOT of a term of a sum (!a + b >= 1) is part of the sum
+	if parser.isPseudoBoolean() {
+		return parser.parsePseudoBoolean()
+	}
+
 	if parser.peekToken().Is(NOT) {
 		parser.pos++
 		next := parser.peekToken()
 		if next.IsOperator() {
-			return nil, fmt.Errorf("expected var or number after a not operator")
+			return nil, parser.errorAt(next, fmt.Sprintf("unexpected %s after a not operator", describeToken(next)), operandTokens...)
 		}
 
 		expr, err := parser.parseNot()
@@ -136,23 +182,30 @@ func (parser *Parser) parseNot() (Expression, error) {
 	return parser.parsePrimary()
 }
 
-// parsePrimary parses primary expressions (variables, numbers, parenthesized expressions)
+// parsePrimary parses primary expressions (variables, numbers, cardinality constraints,
+// parenthesized expressions)
 func (parser *Parser) parsePrimary() (Expression, error) {
 	token := parser.peekToken()
+	if token.Is(VAR) && parser.peekTokenAt(1).Is(LPAREN) {
+		if kind, ok := parseCardinalityKind(token.Value); ok {
+			return parser.parseCardinality(kind)
+		}
+	}
 
 	switch {
 	case token.Is(VAR):
 		parser.pos++
-		next := parser.peekToken()
-		if !next.IsOperator() && !next.Is(EOF) && !next.Is(RPAREN) {
-			return nil, fmt.Errorf("expected operator after a variable")
+		if err := parser.expectOperandEnd("a variable"); err != nil {
+			return nil, err
 		}
 		return NewVarExpression(token.Value), nil
 	case token.Is(NUMBER):
+		if token.Value != "0" && token.Value != "1" {
+			return nil, parser.errorAt(token, fmt.Sprintf("the number %s is not a boolean value, only 0 and 1 are", token.Value))
+		}
 		parser.pos++
-		next := parser.peekToken()
-		if !next.IsOperator() && !next.Is(EOF) && !next.Is(RPAREN) {
-			return nil, fmt.Errorf("expected operator after a number")
+		if err := parser.expectOperandEnd("a number"); err != nil {
+			return nil, err
 		}
 		value, _ := strconv.Atoi(token.Value)
 		return NewNumberExpression(value), nil
@@ -162,21 +215,191 @@ func (parser *Parser) parsePrimary() (Expression, error) {
 		if err != nil {
 			return nil, err
 		}
-		if !parser.peekToken().Is(RPAREN) {
-			return nil, fmt.Errorf("expected closing parenthesis")
+		if next := parser.peekToken(); !next.Is(RPAREN) {
+			return nil, parser.errorAt(next, fmt.Sprintf("missing closing parenthesis, found %s", describeToken(next)), RPAREN)
 		}
 		parser.pos++
 		return expr, nil
 	default:
-		return nil, fmt.Errorf("unexpected token: %s", token.Value)
+		return nil, parser.errorAt(token, fmt.Sprintf("unexpected %s", describeToken(token)), operandTokens...)
+	}
+}
+
+// parseCardinality parses the constraints atleast(k, a, b, ...), atmost(k, ...) and
+// exactly(k, ...), whose operands are expressions
+func (parser *Parser) parseCardinality(kind CardinalityKind) (Expression, error) {
+	parser.pos += 2
+	bound, err := parser.parseInteger()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Expression{}
+	for parser.peekToken().Is(COMMA) {
+		parser.pos++
+		operand, err := parser.parseEquivalence()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
 	}
+
+	if next := parser.peekToken(); !next.Is(RPAREN) {
+		return nil, parser.errorAt(next, fmt.Sprintf("missing closing parenthesis of %s, found %s", kind, describeToken(next)), COMMA, RPAREN)
+	}
+	parser.pos++
+	if err := parser.expectOperandEnd(fmt.Sprintf("%s(...)", kind)); err != nil {
+		return nil, err
+	}
+	return NewCardinalityExpression(kind, bound, operands...), nil
+}
+
+// isPseudoBoolean returns true if the next tokens are a sum of terms [coefficient][!]variable
+// followed by a comparison, such as 2a + !b >= 1. Without a comparison, + is the XOR operator.
+// The sum is an operand of the NOT, AND and OR operators, so it binds tighter than them:
+// c & a + b >= 1 is c & (a + b >= 1). When the terms are not followed by a comparison, the
+// terms starting after the current one are not either, so the sum is only scanned once
+func (parser *Parser) isPseudoBoolean() bool {
+	if parser.pos < parser.plainSumEnd {
+		return false
+	}
+
+	offset := 0
+	for {
+		if parser.peekTokenAt(offset).isInteger() {
+			offset++
+		}
+		if parser.peekTokenAt(offset).Is(NOT) {
+			offset++
+		}
+		if !parser.peekTokenAt(offset).Is(VAR) {
+			break
+		}
+
+		next := parser.peekTokenAt(offset + 1)
+		if next.Is(GREATER_EQUAL) || next.Is(LESS_EQUAL) {
+			return true
+		}
+		if !next.Is(XOR) {
+			break
+		}
+		offset += 2
+	}
+
+	parser.plainSumEnd = parser.pos + offset
+	return false
+}
+
+// parsePseudoBoolean parses a pseudo-Boolean constraint recognized by isPseudoBoolean
+func (parser *Parser) parsePseudoBoolean() (Expression, error) {
+	coefficients, operands := []int{}, []Expression{}
+	for {
+		coefficient := 1
+		if parser.peekToken().isInteger() {
+			value, err := parser.parseInteger()
+			if err != nil {
+				return nil, err
+			}
+			coefficient = value
+		}
+
+		negated := parser.peekToken().Is(NOT)
+		if negated {
+			parser.pos++
+		}
+		var operand Expression = NewVarExpression(parser.peekToken().Value)
+		if negated {
+			operand = NewNotExpression(operand)
+		}
+		parser.pos++
+		coefficients, operands = append(coefficients, coefficient), append(operands, operand)
+
+		if !parser.peekToken().Is(XOR) {
+			break
+		}
+		parser.pos++
+	}
+
+	comparison := GreaterOrEqual
+	if parser.peekToken().Is(LESS_EQUAL) {
+		comparison = LessOrEqual
+	}
+	parser.pos++
+
+	bound, err := parser.parseInteger()
+	if err != nil {
+		return nil, err
+	}
+	if err := parser.expectOperandEnd("a pseudo-Boolean constraint"); err != nil {
+		return nil, err
+	}
+	return NewPseudoBooleanExpression(coefficients, operands, comparison, bound), nil
+}
+
+// parseInteger parses the bound or a coefficient of a constraint
+func (parser *Parser) parseInteger() (int, error) {
+	token := parser.peekToken()
+	if !token.isInteger() {
+		return 0, parser.errorAt(token, fmt.Sprintf("unexpected %s, expected an integer", describeToken(token)))
+	}
+
+	value, err := strconv.Atoi(token.Value)
+	if err != nil {
+		return 0, parser.errorAt(token, fmt.Sprintf("the number %s is too large", token.Value))
+	}
+	parser.pos++
+	return value, nil
+}
+
+// expectOperandEnd returns an error if the token following an operand can not follow it
+func (parser *Parser) expectOperandEnd(operand string) error {
+	next := parser.peekToken()
+	if !next.IsOperator() && !next.Is(EOF) && !next.Is(RPAREN) && !next.Is(COMMA) {
+		return parser.errorAt(next, fmt.Sprintf("unexpected %s after %s", describeToken(next), operand), binaryOperatorTokens...)
+	}
+	return nil
 }
 
 func (parser *Parser) peekToken() Token {
-	if parser.pos >= parser.tokens.Size() {
-		return Token{Type: EOF}
+	return parser.peekTokenAt(0)
+}
+
+/*
+Return the token at the given offset from the current position, EOF after the last one
+*/
+func (parser *Parser) peekTokenAt(offset int) Token {
+	if parser.pos+offset >= parser.tokens.Size() {
+		return parser.eofToken()
 	}
 
-	token, _ := parser.tokens.At(parser.pos)
+	token, _ := parser.tokens.At(parser.pos + offset)
 	return token
 }
+
+/*
+Create the EOF token, located right after the last token of the input
+*/
+func (parser *Parser) eofToken() Token {
+	if parser.tokens.IsEmpty() {
+		return Token{Type: EOF, Span: Span{Start: Position{Line: 1, Column: 1}, End: Position{Line: 1, Column: 1}}}
+	}
+
+	last, _ := parser.tokens.At(parser.tokens.Size() - 1)
+	return Token{Type: EOF, Span: Span{Start: last.Span.End, End: last.Span.End}}
+}
+
+func (parser *Parser) errorAt(token Token, message string, expected ...TokenType) *ParseError {
+	return &ParseError{Message: message, Span: token.Span, Found: token, Expected: expected}
+}
+
+/*
+Return a readable description of a token for error messages
+*/
+func describeToken(token Token) string {
+	switch {
+	case token.Is(VAR), token.Is(NUMBER):
+		return fmt.Sprintf("%s '%s'", token.Type, token.Value)
+	default:
+		return token.Type.String()
+	}
+}
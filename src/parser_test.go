@@ -56,10 +56,10 @@ func TestParseNot(t *testing.T) {
 		{"test not operator with | after", "!|", true, map[string]bool{"a": true}, false},
 		{"test not operator with ) after", "!)", true, map[string]bool{"a": true}, false},
 		{"test simple not operation", "!a", false, map[string]bool{"a": false}, true},
-		{"test not operation with parenthesis", "!(avb)", false, map[string]bool{"a": false, "b": false}, true},
+		{"test not operation with parenthesis", "!(a v b)", false, map[string]bool{"a": false, "b": false}, true},
 		{"test not operation with 1 value", "!1", false, map[string]bool{}, false},
 		{"test not operation with 0 value", "!0", false, map[string]bool{}, true},
-		{"test not operation with an error after the not", "!(av)", true, map[string]bool{}, true},
+		{"test not operation with an error after the not", "!(a v)", true, map[string]bool{}, true},
 	}
 
 	runTestCases(t, tests)
@@ -85,16 +85,16 @@ func TestParseAnd(t *testing.T) {
 
 func TestParseOr(t *testing.T) {
 	tests := []testCase{
-		{"test simple a or a", "ava", false, map[string]bool{"a": true}, true},
+		{"test simple a or a", "a v a", false, map[string]bool{"a": true}, true},
 		{"test or operator with & after", "v&", true, map[string]bool{"a": true}, false},
 		{"test or operator with -> after", "v->", true, map[string]bool{"a": true}, false},
 		{"test or operator with | after", "v|", true, map[string]bool{"a": true}, false},
 		{"test or operator with ) after", "v)", true, map[string]bool{"a": true}, false},
-		{"test simple a or b", "avb", false, map[string]bool{"a": false, "b": true}, true},
-		{"test simple a or b 2", "avb", false, map[string]bool{"a": false, "b": false}, false},
-		{"test simple !a or b ", "!avb", false, map[string]bool{"a": false, "b": true}, true},
-		{"test simple !a or !b", "!av!b", false, map[string]bool{"a": true, "b": true}, false},
-		{"test simple !a or !b or (a or !b)", "!av!bv(av!b)", false, map[string]bool{"a": true, "b": true}, true},
+		{"test simple a or b", "a v b", false, map[string]bool{"a": false, "b": true}, true},
+		{"test simple a or b 2", "a v b", false, map[string]bool{"a": false, "b": false}, false},
+		{"test simple !a or b ", "!a v b", false, map[string]bool{"a": false, "b": true}, true},
+		{"test simple !a or !b", "!a v !b", false, map[string]bool{"a": true, "b": true}, false},
+		{"test simple !a or !b or (a or !b)", "!a v !b v (a v !b)", false, map[string]bool{"a": true, "b": true}, true},
 	}
 
 	runTestCases(t, tests)
@@ -112,7 +112,7 @@ func TestParseImplies(t *testing.T) {
 		{"test simple a -> b 2", "a->b", false, map[string]bool{"a": true, "b": false}, false},
 		{"test simple !a -> b ", "!a->b", false, map[string]bool{"a": false, "b": true}, true},
 		{"test simple !a -> !b", "!a->!b", false, map[string]bool{"a": true, "b": true}, true},
-		{"test simple !a -> !b -> (a or !b)", "!av!bv(av!b)", false, map[string]bool{"a": true, "b": true}, true},
+		{"test simple !a -> !b -> (a or !b)", "!a v !b v (a v !b)", false, map[string]bool{"a": true, "b": true}, true},
 	}
 
 	runTestCases(t, tests)
@@ -130,7 +130,7 @@ func TestParserXOR(t *testing.T) {
 		{"test simple a + b 2", "a+b", false, map[string]bool{"a": true, "b": true}, false},
 		{"test simple !a + b ", "!a+b", false, map[string]bool{"a": false, "b": true}, false},
 		{"test simple !a + !b", "!a+!b", false, map[string]bool{"a": true, "b": false}, true},
-		{"test simple !a + !b + (a or !b)", "!a+!b+(av!b)", false, map[string]bool{"a": true, "b": true}, true},
+		{"test simple !a + !b + (a or !b)", "!a+!b+(a v !b)", false, map[string]bool{"a": true, "b": true}, true},
 	}
 
 	runTestCases(t, tests)
@@ -144,6 +144,9 @@ func TestParserExpression(t *testing.T) {
 		{"test expression (!a)", "(!a)", false, map[string]bool{"a": false}, true},
 		{"test expression without closing right parenthesis !((a)", "!((a)", true, map[string]bool{}, true},
 		{"test expression with invalid char after a left parenthesis", "(+)", true, map[string]bool{}, true},
+		{"test expression with an unexpected closing parenthesis", "a)", true, map[string]bool{}, true},
+		{"test expression with multi-character variables", "enable & !reset_n", false, map[string]bool{"enable": true, "reset_n": false}, true},
+		{"test expression with v as a variable", "v v !v", false, map[string]bool{"v": false}, true},
 	}
 
 	runTestCases(t, tests)
@@ -157,6 +160,19 @@ func TestParserEquivalence(t *testing.T) {
 	runTestCases(t, tests)
 }
 
+func TestParserAlternativeSyntax(t *testing.T) {
+	tests := []testCase{
+		{"test unicode operators", "¬a ∧ b → c ↔ ⊤", false, map[string]bool{"a": false, "b": true, "c": false}, false},
+		{"test unicode xor", "a ⊕ b", false, map[string]bool{"a": true, "b": false}, true},
+		{"test keyword operators", "not a and (b or c) implies d", false, map[string]bool{"a": false, "b": true, "d": false}, false},
+		{"test keyword constants", "a xor TRUE iff false", false, map[string]bool{"a": true}, true},
+		{"test C-style operators", "!(a && b) || c == (a != b)", false, map[string]bool{"a": true, "b": false, "c": false}, true},
+		{"test keyword used as variable", "and & b", true, map[string]bool{}, false},
+	}
+
+	runTestCases(t, tests)
+}
+
 func TestParserNumber(t *testing.T) {
 	tests := []testCase{
 		{"test simple 0 + 1", "0+1", false, map[string]bool{}, true},
@@ -166,3 +182,28 @@ func TestParserNumber(t *testing.T) {
 
 	runTestCases(t, tests)
 }
+
+func TestParserCStylePrecedence(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewVarExpression("a"), NewVarExpression("b"), NewVarExpression("c")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected Expression
+	}{
+		{"test == binds tighter than &&", "a && b == c", NewAndExpression(a, NewEquivalenceExpression(b, c))},
+		{"test != binds tighter than ||", "a != b || c", NewOrExpression(NewXORExpression(a, b), c)},
+		{"test ! binds tighter than ==", "!a == b", NewEquivalenceExpression(NewNotExpression(a), b)},
+		{"test comparisons are left associative", "a == b != c", NewXORExpression(NewEquivalenceExpression(a, b), c)},
+		{"test <-> keeps the lowest precedence", "a && b <-> c", NewEquivalenceExpression(NewAndExpression(a, b), c)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+			assert.True(test.expected.equal(expr), expr.String())
+		})
+	}
+}
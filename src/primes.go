@@ -0,0 +1,295 @@
+package logic
+
+import (
+	"fmt"
+)
+
+/*
+Product of literals over named variables, such as a & !c over the variables a, b, c.
+The literals are kept in the order of the variables
+*/
+type Cube struct {
+	variables []string
+	bits      cube
+}
+
+/*
+Create the cube of the literals, each variable being true or false in the product. The
+variables of the literals must belong to the variables of the cube
+*/
+func NewCube(variables []string, literals map[string]bool) (Cube, error) {
+	if len(variables) > 64 {
+		return Cube{}, fmt.Errorf("a cube supports at most 64 variables, found %d", len(variables))
+	}
+
+	indexes := indexVariables(variables)
+	bits := cube{}
+	for variable, value := range literals {
+		index, ok := indexes[variable]
+		if !ok {
+			return Cube{}, fmt.Errorf("the variable %s of the literals is not a variable of the cube", variable)
+		}
+
+		bit := uint64(1) << index
+		bits.mask |= bit
+		if value {
+			bits.value |= bit
+		}
+	}
+	return Cube{variables: variables, bits: bits}, nil
+}
+
+/*
+Return the variables the cube is defined over, including the ones without literal
+*/
+func (c Cube) Variables() []string {
+	return c.variables
+}
+
+/*
+Return the literals of the cube: the value of each variable of the product
+*/
+func (c Cube) Literals() map[string]bool {
+	literals := make(map[string]bool)
+	for index, variable := range c.variables {
+		if bit := uint64(1) << index; c.bits.mask&bit != 0 {
+			literals[variable] = c.bits.value&bit != 0
+		}
+	}
+	return literals
+}
+
+/*
+Return the number of literals of the cube
+*/
+func (c Cube) Size() int {
+	return c.bits.literals()
+}
+
+/*
+Return true if every literal of the cube is true. Missing variables are false
+*/
+func (c Cube) Contains(assignment map[string]bool) bool {
+	for variable, value := range c.Literals() {
+		if assignment[variable] != value {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return true if every assignment of the other cube belongs to this cube, which is the
+case when the literals of this cube are literals of the other one
+*/
+func (c Cube) Covers(other Cube) bool {
+	otherLiterals := other.Literals()
+	for variable, value := range c.Literals() {
+		if otherValue, ok := otherLiterals[variable]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return the rows of the truth table of the variables of the cube which belong to it,
+numbered as by Minterms
+*/
+func (c Cube) Minterms() []int {
+	minterms := []int{}
+	for row := 0; row < 1<<len(c.variables); row++ {
+		if c.bits.containsMinterm(uint64(row)) {
+			minterms = append(minterms, row)
+		}
+	}
+	return minterms
+}
+
+/*
+Return the cube as a conjunction of literals, 1 when it has no literal
+*/
+func (c Cube) ToExpression() Expression {
+	return c.bits.toExpression(c.variables)
+}
+
+/*
+Return the disjunction of the literals of the cube, 0 when it has no literal. The prime
+implicates are cubes read as clauses
+*/
+func (c Cube) ToClause() Expression {
+	literals := []Expression{}
+	for index, variable := range c.variables {
+		bit := uint64(1) << index
+		if c.bits.mask&bit == 0 {
+			continue
+		}
+
+		var literal Expression = NewVarExpression(variable)
+		if c.bits.value&bit == 0 {
+			literal = NewNotExpression(literal)
+		}
+		literals = append(literals, literal)
+	}
+	return disjunctionOf(literals)
+}
+
+/*
+Return the cube as a conjunction in the ASCII dialect
+*/
+func (c Cube) String() string {
+	return Print(c.ToExpression(), ASCII)
+}
+
+/*
+Return all the prime implicants of the expression: the products implying it that do not
+imply it anymore when a literal is removed. They are computed with the Quine-McCluskey
+algorithm on the truth table, over at most MAX_EXACT_VARIABLES variables
+*/
+func PrimeImplicants(expr Expression) ([]Cube, error) {
+	variables := Variables(expr)
+	if len(variables) > MAX_EXACT_VARIABLES {
+		return nil, fmt.Errorf("the prime implicants are computed for at most %d variables, found %d", MAX_EXACT_VARIABLES, len(variables))
+	}
+
+	primes := []Cube{}
+	if minterms := mintermsOf(expr, variables); len(minterms) > 0 {
+		for _, prime := range primeImplicants(minterms, len(variables)) {
+			primes = append(primes, Cube{variables: variables, bits: prime})
+		}
+	}
+	return primes, nil
+}
+
+/*
+Return all the prime implicates of the expression: the clauses implied by it that are not
+implied anymore when a literal is removed. Each clause is returned as the cube of its
+literals, to be read as their disjunction with ToClause. The prime implicates are the
+negations of the prime implicants of the negation of the expression
+*/
+func PrimeImplicates(expr Expression) ([]Cube, error) {
+	implicants, err := PrimeImplicants(NewNotExpression(expr))
+	if err != nil {
+		return nil, err
+	}
+
+	implicates := []Cube{}
+	for _, implicant := range implicants {
+		bits := cube{value: implicant.bits.mask &^ implicant.bits.value, mask: implicant.bits.mask}
+		implicates = append(implicates, Cube{variables: implicant.variables, bits: bits})
+	}
+	sortExportedCubes(implicates)
+	return implicates, nil
+}
+
+/*
+Return the prime implicants covering a row of the truth table that no other prime
+implicant covers: they belong to every minimal sum of products of the expression
+*/
+func EssentialPrimeImplicants(expr Expression) ([]Cube, error) {
+	primes, err := PrimeImplicants(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	essentials := []Cube{}
+	for index, prime := range primes {
+		if isEssentialPrime(primes, index) {
+			essentials = append(essentials, prime)
+		}
+	}
+	return essentials, nil
+}
+
+/*
+Return true if the prime at the index covers a row covered by no other prime
+*/
+func isEssentialPrime(primes []Cube, index int) bool {
+	for _, minterm := range primes[index].Minterms() {
+		covered := false
+		for other, prime := range primes {
+			if other != index && prime.bits.containsMinterm(uint64(minterm)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Return the Blake canonical form of the expression: the disjunction of all its prime
+implicants. Two expressions are equivalent when they have the same Blake canonical form
+over the same variables
+*/
+func BlakeCanonicalForm(expr Expression) (Expression, error) {
+	primes, err := PrimeImplicants(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []Expression{}
+	for _, prime := range primes {
+		products = append(products, prime.ToExpression())
+	}
+	return disjunctionOf(products), nil
+}
+
+func sortExportedCubes(cubes []Cube) {
+	bits := make([]cube, len(cubes))
+	for index, c := range cubes {
+		bits[index] = c.bits
+	}
+	sortCubes(bits)
+	for index := range cubes {
+		cubes[index].bits = bits[index]
+	}
+}
+
+/*
+Return the products of a sum of products, such as the result of the minimizers, as cubes
+over the variables. The products containing a variable and its negation are removed
+*/
+func CubesOf(expr Expression, variables []string) ([]Cube, error) {
+	cubes := []Cube{}
+	for _, product := range junctionOperands(expr, false) {
+		if number, ok := product.(*NumberExpression); ok && number.value == 0 {
+			continue
+		}
+
+		literals := make(map[string]bool)
+		contradictory := false
+		for _, factor := range junctionOperands(product, true) {
+			value := true
+			if not, ok := factor.(*NotExpression); ok {
+				factor, value = not.expr, false
+			}
+
+			switch literal := factor.(type) {
+			case *VarExpression:
+				if other, ok := literals[literal.variable]; ok && other != value {
+					contradictory = true
+				}
+				literals[literal.variable] = value
+			case *NumberExpression:
+				contradictory = contradictory || (literal.value == 1) != value
+			default:
+				return nil, fmt.Errorf("%s is not a sum of products", Print(expr, ASCII))
+			}
+		}
+
+		if contradictory {
+			continue
+		}
+
+		c, err := NewCube(variables, literals)
+		if err != nil {
+			return nil, err
+		}
+		cubes = append(cubes, c)
+	}
+	return cubes, nil
+}
@@ -0,0 +1,153 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cubeStrings(cubes []Cube) []string {
+	strings := []string{}
+	for _, c := range cubes {
+		strings = append(strings, c.String())
+	}
+	return strings
+}
+
+func TestPrimeImplicantsOfExpressions(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name               string
+		input              string
+		expectedPrimes     []string
+		expectedEssentials []string
+		expectedImplicates []string
+	}{
+		{"test consensus", "a&b | !a&c", []string{"a & b", "!a & c", "b & c"}, []string{"a & b", "!a & c"}, []string{"a | c", "!a | b", "b | c"}},
+		{"test cyclic core", "!a&!b&!c | a&!b&!c | !a&b&!c | a&!b&c | !a&b&c | a&b&c", []string{"a & !b", "a & c", "!a & b", "!a & !c", "b & c", "!b & !c"}, []string{}, []string{"a | b | !c", "!a | !b | c"}},
+		{"test xor", "a + b", []string{"a & !b", "!a & b"}, []string{"a & !b", "!a & b"}, []string{"a | b", "!a | !b"}},
+		{"test tautology", "a | !a", []string{"1"}, []string{"1"}, []string{}},
+		{"test contradiction", "a & !a", []string{}, []string{}, []string{"0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			primes, err := PrimeImplicants(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedPrimes, cubeStrings(primes), test.name)
+
+			essentials, err := EssentialPrimeImplicants(expr)
+			assert.Nil(err)
+			assert.Equal(test.expectedEssentials, cubeStrings(essentials), test.name)
+
+			implicates, err := PrimeImplicates(expr)
+			assert.Nil(err)
+			clauses := []string{}
+			for _, implicate := range implicates {
+				clauses = append(clauses, implicate.ToClause().String())
+			}
+			assert.Equal(test.expectedImplicates, clauses, test.name)
+		})
+	}
+}
+
+func TestPrimeImplicantsRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(39))
+	variables := []string{"a", "b", "c", "d"}
+
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 4, variables)
+		primes, err := PrimeImplicants(expr)
+		assert.Nil(err)
+
+		for _, prime := range primes {
+			// a prime implies the expression, and does not anymore without one of its literals
+			entailed, _ := Entails([]Expression{prime.ToExpression()}, expr)
+			assert.True(entailed, Print(expr, ASCII))
+			for variable := range prime.Literals() {
+				literals := prime.Literals()
+				delete(literals, variable)
+				larger, err := NewCube(prime.Variables(), literals)
+				assert.Nil(err)
+				assert.True(larger.Covers(prime))
+				entailed, _ := Entails([]Expression{larger.ToExpression()}, expr)
+				assert.False(entailed, Print(expr, ASCII))
+			}
+		}
+
+		implicates, err := PrimeImplicates(expr)
+		assert.Nil(err)
+		for _, implicate := range implicates {
+			entailed, _ := Entails([]Expression{expr}, implicate.ToClause())
+			assert.True(entailed, Print(expr, ASCII))
+		}
+
+		// the Blake canonical form is equivalent, and has the same prime implicants
+		blake, err := BlakeCanonicalForm(expr)
+		assert.Nil(err)
+		assert.True(sameTruthTable(expr, blake), Print(expr, ASCII))
+		again, err := PrimeImplicants(blake)
+		assert.Nil(err)
+		assert.Equal(len(primes), len(again), Print(expr, ASCII))
+	}
+}
+
+func TestCube(t *testing.T) {
+	assert := assert.New(t)
+
+	variables := []string{"a", "b", "c"}
+	c, err := NewCube(variables, map[string]bool{"a": true, "c": false})
+	assert.Nil(err)
+	assert.Equal("a & !c", c.String())
+	assert.Equal("a | !c", c.ToClause().String())
+	assert.Equal(2, c.Size())
+	assert.Equal([]int{1, 3}, c.Minterms())
+	assert.True(c.Contains(map[string]bool{"a": true, "b": true}))
+	assert.False(c.Contains(map[string]bool{"a": true, "c": true}))
+
+	_, err = NewCube(variables, map[string]bool{"d": true})
+	assert.Equal("the variable d of the literals is not a variable of the cube", err.Error())
+}
+
+func TestPrimeImplicantsTooManyVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a0 & a1 & a2 & a3 & a4 & a5 & a6 & a7 & a8 & a9 & a10 & a11 & a12 & a13 & a14 & a15 & a16")
+	_, err := PrimeImplicants(expr)
+	assert.Equal("the prime implicants are computed for at most 16 variables, found 17", err.Error())
+}
+
+func TestCubesOf(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"test sum of products", "a & !c | b", []string{"a & !c", "b"}},
+		{"test contradictory product", "a & !a | b & c", []string{"b & c"}},
+		{"test constants", "0 | 1 & a", []string{"a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			cubes, err := CubesOf(expr, []string{"a", "b", "c"})
+			assert.Nil(err)
+			assert.Equal(test.expected, cubeStrings(cubes), test.name)
+		})
+	}
+
+	expr, _ := parseInput("a & (b | c)")
+	_, err := CubesOf(expr, []string{"a", "b", "c"})
+	assert.Equal("a & (b | c) is not a sum of products", err.Error())
+}
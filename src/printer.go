@@ -0,0 +1,260 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Dialect int
+
+// Output dialects of the printer
+const (
+	ASCII    Dialect = iota // !a & b | c + d -> e <-> f
+	Unicode                 // ¬a ∧ b ∨ c ⊕ d → e ↔ f
+	Keywords                // not a and b or c xor d implies e iff f
+	CStyle                  // !a && b || (c != d), usable in C or Go source
+)
+
+// Precedence levels used by the Parser, from the loosest to the tightest
+const (
+	equivalencePrecedence = iota + 1
+	impliesPrecedence
+	xorPrecedence
+	orPrecedence
+	andPrecedence
+	notPrecedence
+	atomPrecedence
+)
+
+// Symbols of a dialect
+type dialectSymbols struct {
+	not, and, or, xor, implies, equivalence, trueValue, falseValue string
+	greaterOrEqual, lessOrEqual                                    string
+}
+
+var dialects = map[Dialect]dialectSymbols{
+	ASCII:    {not: "!", and: " & ", or: " | ", xor: " + ", implies: " -> ", equivalence: " <-> ", trueValue: "1", falseValue: "0", greaterOrEqual: " >= ", lessOrEqual: " <= "},
+	Unicode:  {not: "¬", and: " ∧ ", or: " ∨ ", xor: " ⊕ ", implies: " → ", equivalence: " ↔ ", trueValue: "⊤", falseValue: "⊥", greaterOrEqual: " ≥ ", lessOrEqual: " ≤ "},
+	Keywords: {not: "not ", and: " and ", or: " or ", xor: " xor ", implies: " implies ", equivalence: " iff ", trueValue: "true", falseValue: "false", greaterOrEqual: " >= ", lessOrEqual: " <= "},
+	CStyle:   {not: "!", and: " && ", or: " || ", xor: " != ", equivalence: " == ", trueValue: "true", falseValue: "false", greaterOrEqual: " >= ", lessOrEqual: " <= "},
+}
+
+var dialectNames = map[string]Dialect{
+	"ascii":    ASCII,
+	"unicode":  Unicode,
+	"keywords": Keywords,
+	"c":        CStyle,
+}
+
+/*
+Return the dialect associated to a name (ascii, unicode, keywords or c)
+*/
+func ParseDialect(name string) (Dialect, error) {
+	if dialect, ok := dialectNames[strings.ToLower(name)]; ok {
+		return dialect, nil
+	}
+
+	return ASCII, fmt.Errorf("unknown dialect %s, expected ascii, unicode, keywords or c", name)
+}
+
+/*
+Print an expression in the given dialect, with only the parentheses required by the
+precedence of the Parser. For the ASCII, Unicode and Keywords dialects, parsing the
+result gives back the same expression when its variables are plain identifiers of the
+Lexer. Variables are printed as they are: a dotted name such as user.isAdmin needs a
+Lexer created WithDottedVariables, and variables built in code with a keyword name
+(NewVarExpression("and")) or with other characters can not be parsed again. The linear
+constraints built in code with terms or bounds that the Parser does not read are printed
+as equivalent expressions that it reads.
+
+The CStyle dialect follows the C and Go precedence, where == and != bind tighter than
+&& and ||: these operators are always parenthesized when they are nested, and
+implications are written as !a || b
+*/
+func Print(expr Expression, dialect Dialect) string {
+	printer := printer{dialect: dialect, symbols: dialects[dialect]}
+	return printer.print(expr)
+}
+
+type printer struct {
+	dialect Dialect
+	symbols dialectSymbols
+}
+
+func (printer printer) print(expr Expression) string {
+	switch value := expr.(type) {
+	case *VarExpression:
+		return value.variable
+	case *NumberExpression:
+		if value.value == 0 {
+			return printer.symbols.falseValue
+		}
+		return printer.symbols.trueValue
+	case *NotExpression:
+		return printer.symbols.not + printer.operand(value.expr, notPrecedence, false, expr)
+	case *AndExpression:
+		return printer.binary(expr, value.left, value.right, printer.symbols.and)
+	case *OrExpression:
+		return printer.binary(expr, value.left, value.right, printer.symbols.or)
+	case *XORExpression:
+		return printer.binary(expr, value.left, value.right, printer.symbols.xor)
+	case *ImpliesExpression:
+		if printer.dialect == CStyle {
+			return printer.print(NewOrExpression(NewNotExpression(value.left), value.right))
+		}
+		return printer.binary(expr, value.left, value.right, printer.symbols.implies)
+	case *EquivalenceExpression:
+		return printer.binary(expr, value.left, value.right, printer.symbols.equivalence)
+	case *CardinalityExpression:
+		// the Parser only reads bounds that are not negative, and at least -1 is always true
+		if value.bound < 0 {
+			if value.kind != AtLeast {
+				return printer.print(expandLinearConstraint(value).Simplify())
+			}
+			value = NewCardinalityExpression(AtLeast, 0, value.operands...)
+		}
+		arguments := []string{fmt.Sprint(value.bound)}
+		for _, operand := range value.operands {
+			arguments = append(arguments, printer.print(operand))
+		}
+		return fmt.Sprintf("%s(%s)", value.kind, strings.Join(arguments, ", "))
+	case *PseudoBooleanExpression:
+		return printer.pseudoBoolean(value)
+	default:
+		return expr.String()
+	}
+}
+
+/*
+Print a pseudo-Boolean constraint such as 2a + 3!b + c >= 4. The sum is always written
+with +, which is read as a sum before a comparison. The constraints built in code that
+the Parser can not read are printed as their simplified expansion with AND, OR and NOT
+*/
+func (printer printer) pseudoBoolean(expr *PseudoBooleanExpression) string {
+	readable, ok := readablePseudoBoolean(expr)
+	if !ok {
+		return printer.print(expandLinearConstraint(expr).Simplify())
+	}
+
+	terms := []string{}
+	for index, operand := range readable.operands {
+		term := printer.print(operand)
+		if coefficient := readable.coefficients[index]; coefficient != 1 {
+			term = fmt.Sprint(coefficient) + term
+		}
+		terms = append(terms, term)
+	}
+
+	comparison := printer.symbols.greaterOrEqual
+	if readable.comparison == LessOrEqual {
+		comparison = printer.symbols.lessOrEqual
+	}
+	return strings.Join(terms, " + ") + comparison + fmt.Sprint(readable.bound)
+}
+
+/*
+Return the constraint rewritten with the terms read by the Parser: positive coefficients
+of variables and negated variables, and a bound that is not negative. The zero
+coefficients are dropped, the double negations removed, and a negative coefficient c of
+x becomes -c on !x, the bound being shifted by -c. Return false when an operand is not a
+literal, or when there is no term or the bound stays negative
+*/
+func readablePseudoBoolean(expr *PseudoBooleanExpression) (*PseudoBooleanExpression, bool) {
+	readable := &PseudoBooleanExpression{comparison: expr.comparison, bound: expr.bound}
+	for index, operand := range expr.operands {
+		coefficient := expr.coefficients[index]
+		if coefficient == 0 {
+			continue
+		}
+
+		negated := coefficient < 0
+		for {
+			not, ok := operand.(*NotExpression)
+			if !ok {
+				break
+			}
+			operand, negated = not.expr, !negated
+		}
+		if _, ok := operand.(*VarExpression); !ok {
+			return nil, false
+		}
+
+		if negated {
+			operand = NewNotExpression(operand)
+		}
+		if coefficient < 0 {
+			readable.bound -= coefficient
+			coefficient = -coefficient
+		}
+		readable.coefficients = append(readable.coefficients, coefficient)
+		readable.operands = append(readable.operands, operand)
+	}
+
+	// a sum is never negative, so "sum >= b" is always true when b < 0
+	if readable.comparison == GreaterOrEqual {
+		readable.bound = max(readable.bound, 0)
+	}
+	return readable, len(readable.operands) > 0 && readable.bound >= 0
+}
+
+func (printer printer) binary(parent, left, right Expression, symbol string) string {
+	precedence := precedenceOf(parent)
+	return printer.operand(left, precedence, false, parent) + symbol + printer.operand(right, precedence, true, parent)
+}
+
+/*
+Print an operand, surrounded with parentheses when the Parser would otherwise
+read it differently. All binary operators are left associative
+*/
+func (printer printer) operand(expr Expression, parentPrecedence int, isRight bool, parent Expression) string {
+	precedence := precedenceOf(expr)
+	needParentheses := precedence < parentPrecedence || (isRight && precedence == parentPrecedence)
+
+	// == and != share the same level in C, below ! and above && and ||
+	if printer.dialect == CStyle && precedence < notPrecedence {
+		if isComparison(expr) != isComparison(parent) || (isComparison(expr) && isRight) {
+			needParentheses = true
+		}
+	}
+
+	if needParentheses {
+		return fmt.Sprintf("(%s)", printer.print(expr))
+	}
+
+	return printer.print(expr)
+}
+
+/*
+Return true if the expression is printed as == or != in the CStyle dialect
+*/
+func isComparison(expr Expression) bool {
+	switch expr.(type) {
+	case *XORExpression, *EquivalenceExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+func precedenceOf(expr Expression) int {
+	switch expr.(type) {
+	case *PseudoBooleanExpression:
+		// the Parser reads a sum before its comparison, but it is parenthesized inside
+		// another expression to be readable
+		return 0
+	case *EquivalenceExpression:
+		return equivalencePrecedence
+	case *ImpliesExpression:
+		return impliesPrecedence
+	case *XORExpression:
+		return xorPrecedence
+	case *OrExpression:
+		return orPrecedence
+	case *AndExpression:
+		return andPrecedence
+	case *NotExpression:
+		return notPrecedence
+	default:
+		return atomPrecedence
+	}
+}
@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Generate a random expression over the given variables, used by property tests
+*/
+func randomExpression(random *rand.Rand, depth int, variables []string) Expression {
+	if depth == 0 || random.Intn(4) == 0 {
+		if random.Intn(6) == 0 {
+			return NewNumberExpression(random.Intn(2))
+		}
+		return NewVarExpression(variables[random.Intn(len(variables))])
+	}
+
+	left := randomExpression(random, depth-1, variables)
+	right := randomExpression(random, depth-1, variables)
+	switch random.Intn(6) {
+	case 0:
+		return NewNotExpression(left)
+	case 1:
+		return NewAndExpression(left, right)
+	case 2:
+		return NewOrExpression(left, right)
+	case 3:
+		return NewXORExpression(left, right)
+	case 4:
+		return NewImpliesExpression(left, right)
+	default:
+		return NewEquivalenceExpression(left, right)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	assert := assert.New(t)
+	a, b, c := NewVarExpression("a"), NewVarExpression("b"), NewVarExpression("c")
+
+	tests := []struct {
+		name     string
+		expr     Expression
+		dialect  Dialect
+		expected string
+	}{
+		{"test or inside and", NewAndExpression(NewOrExpression(a, b), c), ASCII, "(a | b) & c"},
+		{"test and inside or", NewOrExpression(NewAndExpression(a, b), c), ASCII, "a & b | c"},
+		{"test left associativity", NewAndExpression(NewAndExpression(a, b), c), ASCII, "a & b & c"},
+		{"test right operand with same precedence", NewAndExpression(a, NewAndExpression(b, c)), ASCII, "a & (b & c)"},
+		{"test right associated implies", NewImpliesExpression(a, NewImpliesExpression(b, c)), ASCII, "a -> (b -> c)"},
+		{"test not of a binary expression", NewNotExpression(NewXORExpression(a, b)), ASCII, "!(a + b)"},
+		{"test double not", NewNotExpression(NewNotExpression(a)), ASCII, "!!a"},
+		{"test constants", NewEquivalenceExpression(NewNumberExpression(1), NewNumberExpression(0)), ASCII, "1 <-> 0"},
+		{"test unicode", NewImpliesExpression(NewAndExpression(NewNotExpression(a), b), NewXORExpression(c, NewNumberExpression(1))), Unicode, "¬a ∧ b → c ⊕ ⊤"},
+		{"test keywords", NewEquivalenceExpression(NewOrExpression(NewNotExpression(a), b), NewNumberExpression(0)), Keywords, "not a or b iff false"},
+		{"test c style comparisons", NewAndExpression(NewXORExpression(a, b), c), CStyle, "(a != b) && c"},
+		{"test c style nested comparisons", NewEquivalenceExpression(c, NewXORExpression(a, b)), CStyle, "c == (a != b)"},
+		{"test c style implies", NewAndExpression(NewImpliesExpression(a, b), c), CStyle, "(!a || b) && c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(test.expected, Print(test.expr, test.dialect), test.name)
+		})
+	}
+}
+
+func TestPrintRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(42))
+	variables := []string{"a", "b", "v", "reset_n"}
+
+	for i := 0; i < 500; i++ {
+		expr := randomExpression(random, 5, variables)
+		for _, dialect := range []Dialect{ASCII, Unicode, Keywords} {
+			printed := Print(expr, dialect)
+			parsed, err := parseInput(printed)
+			assert.Nil(err, printed)
+			assert.True(expr.equal(parsed), printed)
+		}
+	}
+}
+
+func TestPrintCStyleRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(7))
+	variables := []string{"a", "b", "c"}
+
+	// implications are rewritten in C, so only the semantic is preserved
+	for i := 0; i < 200; i++ {
+		expr := randomExpression(random, 4, variables)
+		printed := Print(expr, CStyle)
+		parsed, err := parseInput(printed)
+		assert.Nil(err, printed)
+
+		for row := 0; row < 8; row++ {
+			assignment := map[string]bool{"a": row&1 != 0, "b": row&2 != 0, "c": row&4 != 0}
+			assert.Equal(expr.Eval(assignment), parsed.Eval(assignment), printed)
+		}
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	assert := assert.New(t)
+
+	dialect, err := ParseDialect("Unicode")
+	assert.Nil(err)
+	assert.Equal(Unicode, dialect)
+
+	_, err = ParseDialect("latex")
+	assert.NotNil(err)
+}
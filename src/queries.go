@@ -0,0 +1,110 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Decide whether an expression is true for every assignment. When it is not, the
+counterexample is an assignment for which it is false
+*/
+func IsTautology(expr Expression) (bool, map[string]bool) {
+	counterexample, ok := Solve(NewNotExpression(expr))
+	if ok {
+		return false, counterexample
+	}
+	return true, nil
+}
+
+/*
+Decide whether an expression is false for every assignment. When it is not, the
+witness is an assignment for which it is true
+*/
+func IsContradiction(expr Expression) (bool, map[string]bool) {
+	witness, ok := Solve(expr)
+	if ok {
+		return false, witness
+	}
+	return true, nil
+}
+
+/*
+Decide whether an expression is true for at least one assignment, given as witness
+*/
+func IsSatisfiable(expr Expression) (bool, map[string]bool) {
+	witness, ok := Solve(expr)
+	return ok, witness
+}
+
+/*
+Decide whether the conclusion is true for every assignment satisfying all the premises.
+When it is not, the counterexample satisfies the premises but not the conclusion, and
+gives the values of the variables of the premises and of the conclusion. Without
+premises, the conclusion must be a tautology
+*/
+func Entails(premises []Expression, conclusion Expression) (bool, map[string]bool) {
+	var expr Expression = NewNotExpression(conclusion)
+	for index := len(premises) - 1; index >= 0; index-- {
+		expr = NewAndExpression(premises[index], expr)
+	}
+
+	counterexample, ok := Solve(expr)
+	if ok {
+		return false, counterexample
+	}
+	return true, nil
+}
+
+/*
+Property of an expression checked by the CLI
+*/
+type Check int
+
+const (
+	CheckNone Check = iota
+	CheckTautology
+	CheckContradiction
+	CheckSatisfiable
+)
+
+var checkNames = map[Check]string{
+	CheckNone:          "none",
+	CheckTautology:     "tautology",
+	CheckContradiction: "contradiction",
+	CheckSatisfiable:   "satisfiable",
+}
+
+func (check Check) String() string {
+	return checkNames[check]
+}
+
+/*
+Return the check associated to a name (tautology, contradiction or satisfiable)
+*/
+func ParseCheck(name string) (Check, error) {
+	for check, checkName := range checkNames {
+		if check != CheckNone && strings.EqualFold(name, checkName) {
+			return check, nil
+		}
+	}
+
+	return CheckNone, fmt.Errorf("unknown check '%s', expected tautology, contradiction or satisfiable", name)
+}
+
+/*
+Run a check on an expression, and return whether it holds with the assignment
+explaining the answer: a counterexample for the tautologies and contradictions, a
+witness for the satisfiable expressions
+*/
+func (check Check) Run(expr Expression) (bool, map[string]bool) {
+	switch check {
+	case CheckTautology:
+		return IsTautology(expr)
+	case CheckContradiction:
+		return IsContradiction(expr)
+	case CheckSatisfiable:
+		return IsSatisfiable(expr)
+	}
+	return true, nil
+}
@@ -0,0 +1,170 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTautology(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"test excluded middle", "a | !a", true},
+		{"test peirce", "((a -> b) -> a) -> a", true},
+		{"test constant", "1", true},
+		{"test variable", "a", false},
+		{"test contradiction", "a & !a", false},
+		{"test implication", "a & b -> a | c", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, _ := parseInput(test.input)
+			tautology, counterexample := IsTautology(expr)
+			assert.Equal(test.expected, tautology)
+			if !tautology {
+				assert.False(expr.Eval(counterexample))
+			}
+		})
+	}
+}
+
+func TestIsContradictionAndIsSatisfiable(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"test contradiction", "a & !a", true},
+		{"test constant", "0", true},
+		{"test equivalence", "(a <-> b) & (a + b)", true},
+		{"test variable", "a", false},
+		{"test tautology", "a | !a", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, _ := parseInput(test.input)
+			contradiction, witness := IsContradiction(expr)
+			assert.Equal(test.expected, contradiction)
+
+			satisfiable, model := IsSatisfiable(expr)
+			assert.Equal(!test.expected, satisfiable)
+			if satisfiable {
+				assert.True(expr.Eval(witness))
+				assert.True(expr.Eval(model))
+			} else {
+				assert.Nil(witness)
+			}
+		})
+	}
+}
+
+func TestEntails(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name       string
+		premises   []string
+		conclusion string
+		expected   bool
+	}{
+		{"test modus ponens", []string{"a -> b", "a"}, "b", true},
+		{"test modus tollens", []string{"a -> b", "!b"}, "!a", true},
+		{"test affirming the consequent", []string{"a -> b", "b"}, "a", false},
+		{"test inconsistent premises", []string{"a", "!a"}, "c", true},
+		{"test no premise", []string{}, "a | !a", true},
+		{"test no premise variable", []string{}, "a", false},
+		{"test chain", []string{"a -> b", "b -> c", "c -> d"}, "a -> d", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			premises := []Expression{}
+			for _, input := range test.premises {
+				premise, _ := parseInput(input)
+				premises = append(premises, premise)
+			}
+			conclusion, _ := parseInput(test.conclusion)
+
+			entails, counterexample := Entails(premises, conclusion)
+			assert.Equal(test.expected, entails)
+			if !entails {
+				for _, premise := range premises {
+					assert.True(premise.Eval(counterexample))
+				}
+				assert.False(conclusion.Eval(counterexample))
+			}
+		})
+	}
+}
+
+func TestQueriesOnLargeFormulas(t *testing.T) {
+	assert := assert.New(t)
+
+	// the chain x0 -> x1 -> ... -> x199 has far too many rows for a truth table
+	premises := []Expression{}
+	for index := 0; index < 199; index++ {
+		premises = append(premises, NewImpliesExpression(
+			NewVarExpression(fmt.Sprintf("x%d", index)),
+			NewVarExpression(fmt.Sprintf("x%d", index+1)),
+		))
+	}
+	conclusion := NewImpliesExpression(NewVarExpression("x0"), NewVarExpression("x199"))
+	entails, _ := Entails(premises, conclusion)
+	assert.True(entails)
+
+	entails, counterexample := Entails(premises[1:], conclusion)
+	assert.False(entails)
+	assert.True(counterexample["x0"])
+	assert.False(counterexample["x1"])
+
+	random := rand.New(rand.NewSource(25))
+	planted := make([]bool, 150)
+	for index := range planted {
+		planted[index] = random.Intn(2) == 0
+	}
+	expr := randomThreeSAT(random, 150, 600, planted).ToExpression()
+	satisfiable, witness := IsSatisfiable(expr)
+	assert.True(satisfiable)
+	assert.True(expr.Eval(witness))
+}
+
+func TestParseCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, check := range []Check{CheckTautology, CheckContradiction, CheckSatisfiable} {
+		parsed, err := ParseCheck(check.String())
+		assert.Nil(err)
+		assert.Equal(check, parsed)
+	}
+
+	_, err := ParseCheck("none")
+	assert.Equal("unknown check 'none', expected tautology, contradiction or satisfiable", err.Error())
+}
+
+func TestCheckRun(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a | b")
+	holds, witness := CheckSatisfiable.Run(expr)
+	assert.True(holds)
+	assert.True(expr.Eval(witness))
+
+	holds, counterexample := CheckTautology.Run(expr)
+	assert.False(holds)
+	assert.False(expr.Eval(counterexample))
+
+	holds, witness = CheckContradiction.Run(expr)
+	assert.False(holds)
+	assert.True(expr.Eval(witness))
+}
@@ -3,8 +3,9 @@ package logic
 import (
 	"errors"
 	"fmt"
-	"math"
 	"os"
+	"strconv"
+	"strings"
 
 	boolutil "github.com/dterbah/go-logic/src/utils"
 	"github.com/dterbah/gods/list/arraylist"
@@ -17,71 +18,260 @@ import (
 
 const DOT_GRAPH_IMAGE_PATH = "graph.png"
 
+// Exit codes of the program
+const (
+	EXIT_SUCCESS      = 0 // Every check holds
+	EXIT_CHECK_FAILED = 1 // A check does not hold
+	EXIT_ERROR        = 2 // An input is invalid or an operation failed
+)
+
+// Value shown in the truth table for the don't care rows
+const DONT_CARE_VALUE = "X"
+
+/*
+Options of the Runner, one field per CLI flag
+*/
+type RunnerOptions struct {
+	GenerateGraph       bool
+	GenerateTruthTable  bool
+	SimplifyExpression  bool
+	MinimizeExpression  bool
+	MinimizeHeuristic   bool           // Minimize with the Espresso-like heuristic instead of the exact minimizer
+	EspressoEffort      EspressoEffort // Effort of the heuristic minimizer
+	DontCares           string         // Expression true on the rows that can not occur
+	DottedVariables     bool
+	Dialect             Dialect             // Dialect used to print the expressions
+	DIMACSFile          string              // File where the expression is exported in the DIMACS CNF format
+	CardinalityEncoding CardinalityEncoding // Encoding of the cardinality constraints in the DIMACS file
+	Solve               bool                // Search a model of the expression with the SAT solver
+	Models              int                 // Number of models printed by the SAT solver, all of them when negative
+	Projection          []string
+	CountModels         bool               // Count the models of the expression without the truth table
+	Probabilities       map[string]float64 // Print the probability of the expression when not nil
+	Graph               GraphKind          // Graph generated by GenerateGraph
+	BDDOrder            BDDOrderHeuristic  // Order of the variables of the decision diagrams
+	ComplementEdges     bool               // Draw the decision diagrams with complement edges
+	EquivalentTo        string             // Expression whose equivalence with the input is checked
+	Check               Check              // Property of the expression to check
+	Premises            []string           // Expressions that must entail the input when not empty
+	Constraints         []string           // Constraints "name: expression" checked with the input
+	SoftConstraints     []string           // Soft constraints "name/weight: expression" optimized under the constraints
+	PrimeImplicants     bool               // List the prime implicants, the prime implicates and the Blake canonical form
+	Forms               []NormalForm       // Normal forms printed after the truth table
+	KMap                KMapFormat         // Format of the Karnaugh map, with the groups of the minimized expression
+	KMapFile            string             // File where the Karnaugh map is written instead of the standard output
+	TruthTableFormat    TruthTableFormat   // Format of the truth table
+	OutputFile          string             // File where the truth table is written instead of the standard output
+}
+
 /*
 Struct that will execute the main program
 */
 type Runner struct {
-	input              string
-	generateGraph      bool
-	truthTable         bool
-	simplifyExpression bool
+	input   string
+	options RunnerOptions
 }
 
+/*
+Create a Runner with the historical flags. Use NewRunnerWithOptions for the other options
+*/
 func NewRunner(input string, generateGraph bool, generateTruthTable bool, simplifyExpression bool) *Runner {
-	return &Runner{input: input,
-		generateGraph:      generateGraph,
-		truthTable:         generateTruthTable,
-		simplifyExpression: simplifyExpression,
-	}
+	return NewRunnerWithOptions(input, RunnerOptions{
+		GenerateGraph:      generateGraph,
+		GenerateTruthTable: generateTruthTable,
+		SimplifyExpression: simplifyExpression,
+	})
+}
+
+func NewRunnerWithOptions(input string, options RunnerOptions) *Runner {
+	return &Runner{input: input, options: options}
 }
 
 /*
-Run the program
+Run the program, and return its exit code: EXIT_CHECK_FAILED when a check (such as
+-equiv or -check) does not hold, EXIT_ERROR when an input is invalid
 */
-func (runner Runner) Run() {
-	var simplifiedExpr Expression
-
-	lexer := NewLexer(runner.input)
-	tokens, err := lexer.Tokenize()
+func (runner Runner) Run() int {
+	var simplifiedExpr, minimizedExpr, dontCares Expression
+	exitCode := EXIT_SUCCESS
 
+	variables := set.New(comparator.StringComparator)
+	result, err := runner.parse(runner.input, variables)
 	if err != nil {
-		logrus.Error(err)
-		return
+		runner.printInputError(err, runner.input)
+		return EXIT_ERROR
 	}
 
-	parser := NewParser(tokens)
-	variablesTokens := tokens.Filter(func(element Token) bool {
-		return element.Is(VAR)
-	})
+	if runner.options.DontCares != "" {
+		dontCares, err = runner.parse(runner.options.DontCares, variables)
+		if err != nil {
+			runner.printInputError(err, runner.options.DontCares)
+			return EXIT_ERROR
+		}
+	}
 
-	variables := set.New(comparator.StringComparator)
-	variablesTokens.ForEach(func(element Token, index int) {
-		variables.Add(element.Value)
-	})
+	premises := []Expression{}
+	for _, input := range runner.options.Premises {
+		premise, err := runner.parse(input, variables)
+		if err != nil {
+			runner.printInputError(err, input)
+			return EXIT_ERROR
+		}
+		premises = append(premises, premise)
+	}
 
-	variablesTokens.ForEach(func(element Token, index int) {
-		variables.Add(element.Value)
-	})
+	constraints := []Constraint{}
+	if len(runner.options.Constraints) > 0 {
+		constraints = append(constraints, Constraint{Name: runner.input, Expr: result})
+	}
+	for _, input := range runner.options.Constraints {
+		constraint, err := runner.parseConstraint(input, variables)
+		if err != nil {
+			runner.printInputError(err, input)
+			return EXIT_ERROR
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	problem := NewMaxSAT()
+	for _, input := range runner.options.SoftConstraints {
+		constraint, weight, err := runner.parseSoftConstraint(input, variables)
+		if err == nil {
+			err = problem.AddSoft(constraint, weight)
+		}
+		if err != nil {
+			runner.printInputError(err, input)
+			return EXIT_ERROR
+		}
+	}
 
-	result, err := parser.Parse()
+	if runner.options.EquivalentTo != "" {
+		other, err := runner.parse(runner.options.EquivalentTo, variables)
+		if err != nil {
+			runner.printInputError(err, runner.options.EquivalentTo)
+			return EXIT_ERROR
+		}
+		if !runner.printEquivalence(result, other) {
+			exitCode = EXIT_CHECK_FAILED
+		}
+	}
 
-	if err != nil {
-		fmt.Println(err)
-		return
+	if runner.options.Check != CheckNone && !runner.printCheck(result) {
+		exitCode = EXIT_CHECK_FAILED
+	}
+
+	if len(premises) > 0 && !runner.printEntailment(premises, result) {
+		exitCode = EXIT_CHECK_FAILED
+	}
+
+	if len(constraints) > 0 && !runner.printConsistency(constraints) {
+		exitCode = EXIT_CHECK_FAILED
+	}
+
+	if len(runner.options.SoftConstraints) > 0 {
+		// the hard constraints are the expression and the named constraints
+		problem.AddHard(Constraint{Name: runner.input, Expr: result})
+		for _, constraint := range constraints[min(1, len(constraints)):] {
+			problem.AddHard(constraint)
+		}
+		if !runner.printOptimum(problem) {
+			exitCode = EXIT_CHECK_FAILED
+		}
+	}
+
+	if runner.options.DIMACSFile != "" {
+		if err := exportDIMACS(result, runner.options.DIMACSFile, runner.options.CardinalityEncoding); err != nil {
+			fmt.Println(err)
+			fmt.Println("❌ Error during the export of the DIMACS file")
+			exitCode = EXIT_ERROR
+		} else {
+			fmt.Printf("✅ DIMACS file %s created !\n", runner.options.DIMACSFile)
+		}
+	}
+
+	if runner.options.Solve {
+		runner.printModel(result)
+	}
+
+	if runner.options.Models != 0 {
+		runner.printModels(result)
+	}
+
+	if runner.options.CountModels {
+		fmt.Printf("%s model(s) over %d variable(s)\n", CountModels(result), len(Variables(result)))
+	}
+
+	if runner.options.Probabilities != nil {
+		probability, err := Probability(result, runner.options.Probabilities)
+		if err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		} else {
+			fmt.Printf("Probability : %g\n", probability)
+		}
 	}
 
-	if runner.simplifyExpression {
+	if runner.options.SimplifyExpression {
 		simplifiedExpr = result.Simplify()
 	}
 
-	if runner.truthTable {
-		runner.generateTruthTable(result, *variables, simplifiedExpr)
+	if runner.options.MinimizeExpression || runner.options.MinimizeHeuristic {
+		noDontCare := dontCares
+		if noDontCare == nil {
+			noDontCare = NewNumberExpression(0)
+		}
+
+		if runner.options.MinimizeHeuristic {
+			minimizedExpr, err = MinimizeHeuristicWithDontCares(result, noDontCare, runner.options.EspressoEffort)
+		} else {
+			minimizedExpr, err = MinimizeWithDontCares(result, noDontCare)
+		}
+
+		if err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		} else if !runner.options.GenerateTruthTable {
+			fmt.Printf("Minimized : %s\n", Print(minimizedExpr, runner.options.Dialect))
+		}
 	}
 
-	if runner.generateGraph {
+	if runner.options.GenerateTruthTable {
+		if err := runner.generateTruthTable(result, *variables, dontCares, simplifiedExpr, minimizedExpr); err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		}
+	}
+
+	if runner.options.KMap != KMapNone {
+		if err := runner.printKMap(result, *variables, dontCares, minimizedExpr); err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		}
+	}
+
+	if runner.options.PrimeImplicants {
+		if err := runner.printPrimes(result); err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		}
+	}
+
+	if len(runner.options.Forms) > 0 {
+		if err := runner.printForms(result); err != nil {
+			runner.printError(err)
+			exitCode = EXIT_ERROR
+		}
+	}
+
+	if runner.options.GenerateGraph {
 		fmt.Println("🚀 Dot Graph is being generated ...")
 		var graph string
-		if simplifiedExpr != nil {
+		if runner.options.Graph == GraphBDD {
+			// the decision diagram is the same for the simplified and minimized expressions
+			graph = GenerateBDDDot(result, BDDDotOptions{Order: runner.options.BDDOrder, ComplementEdges: runner.options.ComplementEdges})
+		} else if minimizedExpr != nil {
+			graph = GenerateDot(minimizedExpr)
+		} else if simplifiedExpr != nil {
 			graph = GenerateDot(simplifiedExpr)
 		} else {
 			graph = GenerateDot(result)
@@ -92,10 +282,105 @@ func (runner Runner) Run() {
 		if err != nil {
 			fmt.Println(err)
 			fmt.Println("❌ Error during the generation of graph")
+			exitCode = EXIT_ERROR
 		} else {
 			fmt.Println("✅ Graph created !")
 		}
 	}
+
+	return exitCode
+}
+
+/*
+Parse an input of the command line, and add its variables to the set
+*/
+func (runner Runner) parse(input string, variables *set.Set[string]) (Expression, error) {
+	lexer := NewLexer(input)
+	if runner.options.DottedVariables {
+		lexer.WithDottedVariables()
+	}
+
+	tokens, err := lexer.Tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens.ForEach(func(element Token, index int) {
+		// a name followed by a parenthesis is a cardinality constraint such as atmost(...)
+		next, _ := tokens.At(index + 1)
+		if element.Is(VAR) && !next.Is(LPAREN) {
+			variables.Add(element.Value)
+		}
+	})
+
+	return NewParser(tokens).Parse()
+}
+
+/*
+Parse a constraint written as "name: expression". Without a name, the constraint is
+named by its expression
+*/
+func (runner Runner) parseConstraint(input string, variables *set.Set[string]) (Constraint, error) {
+	name, text, found := strings.Cut(input, ":")
+	if !found {
+		name, text = input, input
+	}
+
+	// the name is replaced by spaces, so that the parse errors point into the input
+	name = strings.TrimSpace(name)
+	if found {
+		text = strings.Repeat(" ", len(input)-len(text)) + text
+	}
+	if name == "" {
+		return Constraint{}, fmt.Errorf("the constraint '%s' has an empty name", input)
+	}
+
+	expr, err := runner.parse(text, variables)
+	if err != nil {
+		return Constraint{}, err
+	}
+	return Constraint{Name: name, Expr: expr}, nil
+}
+
+/*
+Parse a soft constraint written as "name/weight: expression", the weight being 1 when
+it is not given
+*/
+func (runner Runner) parseSoftConstraint(input string, variables *set.Set[string]) (Constraint, int, error) {
+	constraint, err := runner.parseConstraint(input, variables)
+	if err != nil {
+		return Constraint{}, 0, err
+	}
+
+	name, weightText, found := strings.Cut(constraint.Name, "/")
+	if !found {
+		return constraint, 1, nil
+	}
+
+	weight, err := strconv.Atoi(strings.TrimSpace(weightText))
+	if err != nil {
+		return Constraint{}, 0, fmt.Errorf("invalid weight '%s' of the soft constraint '%s'", weightText, input)
+	}
+	constraint.Name = strings.TrimSpace(name)
+	return constraint, weight, nil
+}
+
+/*
+Print an error. Parse errors are followed by the input with the invalid part underlined
+*/
+func (runner Runner) printError(err error) {
+	runner.printInputError(err, runner.input)
+}
+
+func (runner Runner) printInputError(err error, input string) {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		logrus.Error(parseError.Error())
+		fmt.Println(parseError.Snippet(input))
+		return
+	}
+
+	logrus.Error(err)
 }
 
 func exportDotGraph(dotGraph string) error {
@@ -114,73 +399,374 @@ func exportDotGraph(dotGraph string) error {
 	return nil
 }
 
-func createTruthTableData(expr Expression, variables set.Set[string], simplifiedExpr Expression) [][]string {
-	nbrVariables := variables.Size()
-	iterations := int(math.Pow(2, float64(nbrVariables)))
+/*
+Print a model of the expression found by the SAT solver, or that it is unsatisfiable
+*/
+func (runner Runner) printModel(expr Expression) {
+	model, ok := Solve(expr)
+	if !ok {
+		fmt.Println("Unsatisfiable")
+		return
+	}
+
+	fmt.Printf("Satisfiable : %s\n", formatAssignment(model, Variables(expr)))
+}
+
+/*
+Print the normal forms of the expression, in the order of the options
+*/
+func (runner Runner) printForms(expr Expression) error {
+	for _, form := range runner.options.Forms {
+		switch form {
+		case FormDNF:
+			dnf, err := ToDNF(expr)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("DNF : %s\n", Print(dnf.ToExpression(), runner.options.Dialect))
+		case FormCNF:
+			cnf, err := ToCNF(expr)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("CNF : %s\n", Print(cnf.ToExpression(), runner.options.Dialect))
+		case FormNNF:
+			fmt.Printf("NNF : %s\n", Print(ToNNF(expr), runner.options.Dialect))
+		case FormANF:
+			anf, err := ToANF(expr)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("ANF : %s (degree %d)\n", Print(anf.ToExpression(), runner.options.Dialect), anf.Degree())
+		case FormMinterms:
+			sum, err := SumOfMinterms(expr)
+			if err != nil {
+				return err
+			}
+			notation, _ := MintermNotation(expr)
+			fmt.Printf("Sum of minterms : %s\n", Print(sum, runner.options.Dialect))
+			fmt.Printf("Minterms : %s\n", notation)
+		case FormMaxterms:
+			product, err := ProductOfMaxterms(expr)
+			if err != nil {
+				return err
+			}
+			notation, _ := MaxtermNotation(expr)
+			fmt.Printf("Product of maxterms : %s\n", Print(product, runner.options.Dialect))
+			fmt.Printf("Maxterms : %s\n", notation)
+		}
+	}
+	return nil
+}
+
+/*
+Print the Karnaugh map of the truth table, with the groups of the minimized expression
+when the expression is minimized
+*/
+func (runner Runner) printKMap(expr Expression, variables set.Set[string], dontCares Expression, minimizedExpr Expression) error {
+	names := variables.ToArray()
+	table := NewTruthTable(names, TruthTableColumn{Name: runner.input, Expr: expr, DontCares: dontCares})
+	kmap, err := NewKarnaughMapFromValues(names, table.Column(0))
+	if err != nil {
+		return err
+	}
 
-	data := [][]string{}
+	if minimizedExpr != nil {
+		groups, err := CubesOf(minimizedExpr, names)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			if err := kmap.AddGroup(group); err != nil {
+				return err
+			}
+		}
+	}
 
-	for i := 0; i < iterations; i++ {
-		variablesMap := make(map[string]bool)
-		tableRow := []string{}
-		// read all first bits of i
-		variables.ForEach(func(element string, index int) {
-			mask := int(math.Pow(2, float64(index)))
-			value := (i & mask) != 0
-			variablesMap[element] = (i & mask) != 0
+	options := KMapOptions{Format: runner.options.KMap, Dialect: runner.options.Dialect}
+	if runner.options.KMapFile == "" {
+		options.Colors = os.Getenv("NO_COLOR") == ""
+		return kmap.Write(os.Stdout, options)
+	}
 
-			tableRow = append(tableRow, boolutil.BoolToString(value))
-		})
+	file, err := os.Create(runner.options.KMapFile)
+	if err != nil {
+		return err
+	}
+	if err := kmap.Write(file, options); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Karnaugh map %s created !\n", runner.options.KMapFile)
+	return nil
+}
 
-		result := expr.Eval(variablesMap)
-		tableRow = append(tableRow, boolutil.BoolToString(result))
+/*
+Print the prime implicants with the rows they cover, the essential ones being marked,
+then the prime implicates and the Blake canonical form
+*/
+func (runner Runner) printPrimes(expr Expression) error {
+	primes, err := PrimeImplicants(expr)
+	if err != nil {
+		return err
+	}
+	implicates, err := PrimeImplicates(expr)
+	if err != nil {
+		return err
+	}
 
-		if simplifiedExpr != nil {
-			tableRow = append(tableRow, boolutil.BoolToString(result))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeader([]string{"Prime implicant", "Minterms", "Essential"})
+	for index, prime := range primes {
+		essential := ""
+		if isEssentialPrime(primes, index) {
+			essential = "✅"
 		}
+		table.Append([]string{Print(prime.ToExpression(), runner.options.Dialect), formatMinterms(prime.Minterms()), essential})
+	}
+	table.Render()
 
-		data = append(data, tableRow)
+	clauses := []Expression{}
+	for _, implicate := range implicates {
+		clauses = append(clauses, implicate.ToClause())
 	}
+	blake, _ := BlakeCanonicalForm(expr)
+	fmt.Printf("Prime implicates : %s\n", Print(conjunctionOf(clauses), runner.options.Dialect))
+	fmt.Printf("Blake canonical form : %s\n", Print(blake, runner.options.Dialect))
+	return nil
+}
 
-	return data
+/*
+Return the rows of a cube as in the textbooks, such as m(1, 3)
+*/
+func formatMinterms(minterms []int) string {
+	numbers := make([]string, len(minterms))
+	for index, minterm := range minterms {
+		numbers[index] = fmt.Sprint(minterm)
+	}
+	return fmt.Sprintf("m(%s)", strings.Join(numbers, ", "))
 }
 
-func (runner Runner) generateTruthTable(expr Expression, variables set.Set[string], simplifiedExpr Expression) {
-	data := createTruthTableData(expr, variables, simplifiedExpr)
+/*
+Print whether the expressions are equivalent, or the row of the truth table on which
+they differ
+*/
+func (runner Runner) printEquivalence(expr Expression, other Expression) bool {
+	equivalent, counterexample := Equivalent(expr, other)
+	if equivalent {
+		fmt.Println("✅ The expressions are equivalent")
+		return true
+	}
+
+	fmt.Println("❌ The expressions are not equivalent, counterexample :")
+	variables := variablesOf(expr, other)
+	row := []string{}
+	for _, variable := range variables {
+		row = append(row, boolutil.BoolToString(counterexample[variable]))
+	}
+	row = append(row, boolutil.BoolToString(expr.Eval(counterexample)), boolutil.BoolToString(other.Eval(counterexample)))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeader(append(variables, runner.input, runner.options.EquivalentTo))
+	table.Append(row)
+	table.Render()
+	return false
+}
+
+/*
+Print whether the expression has the property of the check, with the assignment
+explaining the answer
+*/
+func (runner Runner) printCheck(expr Expression) bool {
+	check := runner.options.Check
+	holds, assignment := check.Run(expr)
+
+	article := "a "
+	if check == CheckSatisfiable {
+		article = ""
+	}
+	// the assignment is a model of the expression, except for the tautologies
+	assignmentName := "witness"
+	if check == CheckTautology {
+		assignmentName = "counterexample"
+	}
+
+	if holds {
+		fmt.Printf("✅ The expression is %s%s%s\n", article, check, describeAssignment(assignmentName, assignment, Variables(expr)))
+	} else {
+		fmt.Printf("❌ The expression is not %s%s%s\n", article, check, describeAssignment(assignmentName, assignment, Variables(expr)))
+	}
+	return holds
+}
+
+/*
+Print whether the premises entail the expression, or an assignment satisfying the
+premises but not the expression
+*/
+func (runner Runner) printEntailment(premises []Expression, conclusion Expression) bool {
+	entails, counterexample := Entails(premises, conclusion)
+	if entails {
+		fmt.Println("✅ The premises entail the expression")
+		return true
+	}
+
+	variables := variablesOf(append(premises, conclusion)...)
+	fmt.Printf("❌ The premises do not entail the expression%s\n", describeAssignment("counterexample", counterexample, variables))
+	return false
+}
+
+/*
+Print whether the constraints are satisfiable together, or a minimal subset of them
+that is contradictory
+*/
+func (runner Runner) printConsistency(constraints []Constraint) bool {
+	mus, unsatisfiable := MinimalUnsatisfiableSubset(constraints)
+	if !unsatisfiable {
+		fmt.Println("✅ The constraints are consistent")
+		return true
+	}
+
+	fmt.Println("❌ The constraints are contradictory, these ones can not be true together :")
+	for _, name := range mus {
+		fmt.Printf("  - %s\n", name)
+	}
+	return false
+}
+
+/*
+Print an assignment satisfying the hard constraints with a minimal cost, and the soft
+constraints it violates
+*/
+func (runner Runner) printOptimum(problem *MaxSAT) bool {
+	optimum, ok := problem.Solve()
+	if !ok {
+		fmt.Println("❌ The hard constraints are unsatisfiable")
+		return false
+	}
+
+	violated := "none"
+	if len(optimum.Violated) > 0 {
+		violated = strings.Join(optimum.Violated, ", ")
+	}
+	fmt.Printf("Optimal cost : %d\n", optimum.Cost)
+	fmt.Printf("Violated soft constraints : %s\n", violated)
+	fmt.Printf("Assignment : %s\n", formatAssignment(optimum.Assignment, problem.variables()))
+	return true
+}
+
+/*
+Return ", <name> : a=1, b=0", or nothing when there is no assignment to show
+*/
+func describeAssignment(name string, assignment map[string]bool, variables []string) string {
+	if assignment == nil || len(variables) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %s : %s", name, formatAssignment(assignment, variables))
+}
+
+/*
+Print the models of the expression one by one, as the SAT solver finds them
+*/
+func (runner Runner) printModels(expr Expression) {
+	limit := runner.options.Models
+	if limit < 0 {
+		limit = 0
+	}
+
+	iterator := Models(expr, ModelOptions{Projection: runner.options.Projection, Limit: limit})
+	count := 0
+	for model, ok := iterator.Next(); ok; model, ok = iterator.Next() {
+		count++
+		fmt.Printf("Model %d : %s\n", count, formatAssignment(model, iterator.Variables()))
+	}
+	fmt.Printf("%d model(s) found\n", count)
+}
+
+/*
+Return the values of the variables, such as "a=1, b=0"
+*/
+func formatAssignment(assignment map[string]bool, variables []string) string {
+	values := []string{}
+	for _, variable := range variables {
+		values = append(values, fmt.Sprintf("%s=%s", variable, boolutil.BoolToString(assignment[variable])))
+	}
+	return strings.Join(values, ", ")
+}
+
+func exportDIMACS(expr Expression, path string, encoding CardinalityEncoding) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteDIMACSWithEncoding(file, expr, encoding); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+/*
+Print the truth table of the expression, with the columns of the simplified and minimized
+expressions, or write it to the output file
+*/
+func (runner Runner) generateTruthTable(expr Expression, variables set.Set[string], dontCares Expression, simplifiedExpr Expression, minimizedExpr Expression) error {
+	columns := []TruthTableColumn{{Name: runner.input, Expr: expr, DontCares: dontCares}}
+	if simplifiedExpr != nil {
+		columns = append(columns, TruthTableColumn{Expr: simplifiedExpr})
+	}
+	if minimizedExpr != nil {
+		columns = append(columns, TruthTableColumn{Expr: minimizedExpr})
+	}
+
+	table := NewTruthTable(variables.ToArray(), columns...)
 	finalSimplifiedExpr := simplifiedExpr
 
-	// Check if the last column has only 1 or 0
+	// Check if the column of the expression has only 1 or 0, the don't care rows can be both
 	col := arraylist.New(comparator.StringComparator)
-
-	for index, row := range data {
-		element := data[index][len(row)-1]
+	for _, element := range table.Column(0) {
 		col.Add(element)
 	}
 
 	if col.Every(func(element string, index int) bool {
-		return element == "1"
+		return element == "1" || element == DONT_CARE_VALUE
 	}) {
 		finalSimplifiedExpr = NewNumberExpression(1)
 	}
 
 	if col.Every(func(element string, index int) bool {
-		return element == "0"
+		return element == "0" || element == DONT_CARE_VALUE
 	}) {
 		finalSimplifiedExpr = NewNumberExpression(0)
 	}
 
-	headers := append(variables.ToArray(), runner.input)
 	if simplifiedExpr != nil {
-		headers = append(headers, fmt.Sprintf("Simplified : %s", finalSimplifiedExpr.String()))
+		table.Columns[1] = fmt.Sprintf("Simplified : %s", Print(finalSimplifiedExpr, runner.options.Dialect))
+	}
+	if minimizedExpr != nil {
+		table.Columns[len(table.Columns)-1] = fmt.Sprintf("Minimized : %s", Print(minimizedExpr, runner.options.Dialect))
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader(headers)
-
-	// display the truth table
-	for _, v := range data {
-		table.Append(v)
+	if runner.options.OutputFile == "" {
+		return table.Write(os.Stdout, runner.options.TruthTableFormat)
 	}
 
-	table.Render()
+	file, err := os.Create(runner.options.OutputFile)
+	if err != nil {
+		return err
+	}
+	if err := table.Write(file, runner.options.TruthTableFormat); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Truth table %s created !\n", runner.options.OutputFile)
+	return nil
 }
@@ -0,0 +1,726 @@
+package logic
+
+import (
+	"sort"
+)
+
+// Number of conflicts between two restarts, multiplied by the Luby sequence
+const SAT_RESTART_UNIT = 100
+
+// Decay of the activity of the variables after each conflict
+const SAT_VARIABLE_DECAY = 0.95
+
+// Decay of the activity of the learnt clauses after each conflict
+const SAT_CLAUSE_DECAY = 0.999
+
+/*
+Statistics of the searches of a SAT solver
+*/
+type SATStatistics struct {
+	Decisions     int
+	Conflicts     int
+	Propagations  int
+	Restarts      int
+	LearntClauses int
+}
+
+type satClause struct {
+	literals []Literal
+	learnt   bool
+	activity float64
+}
+
+/*
+CDCL SAT solver: unit propagation with two watched literals, first UIP clause learning
+with non-chronological backjumping, VSIDS decisions with phase saving, and Luby restarts.
+Clauses can be added between two calls to Solve, and each call can assume the values
+of some literals, which makes the solver incremental
+*/
+type SATSolver struct {
+	clauses           []*satClause
+	learnts           []*satClause
+	watches           [][]*satClause // Clauses watching each literal, indexed by literalIndex
+	assigns           []int8         // 1 for true, -1 for false and 0 when unassigned, indexed by variable
+	levels            []int
+	reasons           []*satClause
+	trail             []Literal
+	trailLimits       []int // Size of the trail at the start of each decision level
+	queueHead         int
+	activity          []float64
+	activityIncrement float64
+	clauseIncrement   float64
+	polarity          []bool
+	seen              []bool
+	order             variableHeap
+	maxLearnts        float64
+	model             []bool
+	conflict          []Literal
+	unsat             bool
+	statistics        SATStatistics
+}
+
+/*
+Create a solver with the variables numbered from 1 to nbrVariables. More variables are
+added by NewVariable or when a clause uses them
+*/
+func NewSATSolver(nbrVariables int) *SATSolver {
+	solver := &SATSolver{
+		watches:           make([][]*satClause, 2),
+		assigns:           make([]int8, 1),
+		levels:            make([]int, 1),
+		reasons:           make([]*satClause, 1),
+		activity:          make([]float64, 1),
+		polarity:          make([]bool, 1),
+		seen:              make([]bool, 1),
+		activityIncrement: 1,
+		clauseIncrement:   1,
+	}
+	solver.order.activity = &solver.activity
+	solver.order.positions = []int{-1}
+	solver.ensureVariable(nbrVariables)
+	return solver
+}
+
+/*
+Create a solver with the clauses of a CNF
+*/
+func NewSATSolverFromCNF(cnf *CNF) *SATSolver {
+	solver := NewSATSolver(len(cnf.Variables))
+	for _, clause := range cnf.Clauses {
+		solver.AddClause(clause...)
+	}
+	return solver
+}
+
+/*
+Return the number of variables of the solver
+*/
+func (solver *SATSolver) NbrVariables() int {
+	return len(solver.assigns) - 1
+}
+
+/*
+Add a variable and return its positive literal
+*/
+func (solver *SATSolver) NewVariable() Literal {
+	solver.ensureVariable(solver.NbrVariables() + 1)
+	return Literal(solver.NbrVariables())
+}
+
+func (solver *SATSolver) ensureVariable(nbrVariables int) {
+	for variable := solver.NbrVariables() + 1; variable <= nbrVariables; variable++ {
+		solver.watches = append(solver.watches, nil, nil)
+		solver.assigns = append(solver.assigns, 0)
+		solver.levels = append(solver.levels, 0)
+		solver.reasons = append(solver.reasons, nil)
+		solver.activity = append(solver.activity, 0)
+		solver.polarity = append(solver.polarity, false)
+		solver.seen = append(solver.seen, false)
+		solver.order.positions = append(solver.order.positions, -1)
+		solver.order.insert(variable)
+	}
+}
+
+/*
+Add a clause. Return false if the clauses are now unsatisfiable without assumption
+*/
+func (solver *SATSolver) AddClause(literals ...Literal) bool {
+	solver.cancelUntil(0)
+	if solver.unsat {
+		return false
+	}
+
+	clause, ok := normalizeClause(literals)
+	if !ok {
+		return true
+	}
+
+	kept := []Literal{}
+	for _, literal := range clause {
+		solver.ensureVariable(literal.Variable())
+		switch solver.value(literal) {
+		case 1:
+			return true
+		case 0:
+			kept = append(kept, literal)
+		}
+	}
+
+	switch len(kept) {
+	case 0:
+		solver.unsat = true
+	case 1:
+		solver.enqueue(kept[0], nil)
+		if solver.propagate() != nil {
+			solver.unsat = true
+		}
+	default:
+		c := &satClause{literals: kept}
+		solver.clauses = append(solver.clauses, c)
+		solver.attach(c)
+	}
+	return !solver.unsat
+}
+
+/*
+Search a model of the clauses where the assumptions are true. When no model exists,
+FailedAssumptions returns the assumptions that can not be true together
+*/
+func (solver *SATSolver) Solve(assumptions ...Literal) bool {
+	solver.model, solver.conflict = nil, nil
+	solver.cancelUntil(0)
+	if solver.unsat {
+		return false
+	}
+
+	for _, assumption := range assumptions {
+		solver.ensureVariable(assumption.Variable())
+	}
+
+	if solver.maxLearnts == 0 {
+		solver.maxLearnts = float64(len(solver.clauses))/3 + 1000
+	}
+
+	for restart := 0; ; restart++ {
+		status := solver.search(lubySequence(restart)*SAT_RESTART_UNIT, assumptions)
+		if status != 0 {
+			solver.cancelUntil(0)
+			return status > 0
+		}
+		solver.statistics.Restarts++
+	}
+}
+
+/*
+Return the value of a literal in the model found by the last call to Solve
+*/
+func (solver *SATSolver) Value(literal Literal) bool {
+	return solver.model[literal.Variable()] != literal.IsNegated()
+}
+
+/*
+Return the assumptions that made the last call to Solve fail, empty when the clauses
+are unsatisfiable whatever the assumptions
+*/
+func (solver *SATSolver) FailedAssumptions() []Literal {
+	return solver.conflict
+}
+
+/*
+Return the statistics of the searches done so far
+*/
+func (solver *SATSolver) Statistics() SATStatistics {
+	statistics := solver.statistics
+	statistics.LearntClauses = len(solver.learnts)
+	return statistics
+}
+
+/*
+Search until a model is found (1), the clauses are unsatisfiable under the assumptions
+(-1), or the number of conflicts reaches the budget (0)
+*/
+func (solver *SATSolver) search(budget int, assumptions []Literal) int {
+	for conflicts := 0; ; {
+		if conflict := solver.propagate(); conflict != nil {
+			solver.statistics.Conflicts++
+			conflicts++
+			if solver.decisionLevel() == 0 {
+				solver.unsat = true
+				return -1
+			}
+
+			learnt, level := solver.analyze(conflict)
+			solver.cancelUntil(level)
+			if len(learnt) == 1 {
+				solver.enqueue(learnt[0], nil)
+			} else {
+				c := &satClause{literals: learnt, learnt: true}
+				solver.learnts = append(solver.learnts, c)
+				solver.attach(c)
+				solver.bumpClause(c)
+				solver.enqueue(learnt[0], c)
+			}
+
+			solver.activityIncrement /= SAT_VARIABLE_DECAY
+			solver.clauseIncrement /= SAT_CLAUSE_DECAY
+			continue
+		}
+
+		if conflicts >= budget {
+			solver.cancelUntil(0)
+			return 0
+		}
+
+		if float64(len(solver.learnts)-len(solver.trail)) >= solver.maxLearnts {
+			solver.reduceLearnts()
+		}
+
+		var next Literal
+		for solver.decisionLevel() < len(assumptions) {
+			assumption := assumptions[solver.decisionLevel()]
+			if value := solver.value(assumption); value > 0 {
+				// already true, the level stays empty
+				solver.trailLimits = append(solver.trailLimits, len(solver.trail))
+			} else if value < 0 {
+				solver.conflict = solver.analyzeFinal(assumption)
+				return -1
+			} else {
+				next = assumption
+				break
+			}
+		}
+
+		if next == 0 {
+			variable := solver.pickBranchVariable()
+			if variable == 0 {
+				solver.model = make([]bool, len(solver.assigns))
+				for variable, value := range solver.assigns {
+					solver.model[variable] = value > 0
+				}
+				return 1
+			}
+
+			next = Literal(variable)
+			if !solver.polarity[variable] {
+				next = next.Not()
+			}
+			solver.statistics.Decisions++
+		}
+
+		solver.trailLimits = append(solver.trailLimits, len(solver.trail))
+		solver.enqueue(next, nil)
+	}
+}
+
+/*
+Propagate the assigned literals, and return the clause in conflict if any. The clauses
+watch their two first literals, and are only visited when one of them becomes false
+*/
+func (solver *SATSolver) propagate() *satClause {
+	for solver.queueHead < len(solver.trail) {
+		falseLiteral := solver.trail[solver.queueHead].Not()
+		solver.queueHead++
+		solver.statistics.Propagations++
+
+		watchers := solver.watches[literalIndex(falseLiteral)]
+		kept := watchers[:0]
+		for index := 0; index < len(watchers); index++ {
+			c := watchers[index]
+			literals := c.literals
+			if literals[0] == falseLiteral {
+				literals[0], literals[1] = literals[1], literals[0]
+			}
+
+			if solver.value(literals[0]) > 0 {
+				kept = append(kept, c)
+				continue
+			}
+
+			moved := false
+			for position := 2; position < len(literals); position++ {
+				if solver.value(literals[position]) >= 0 {
+					literals[1], literals[position] = literals[position], literals[1]
+					solver.watches[literalIndex(literals[1])] = append(solver.watches[literalIndex(literals[1])], c)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			kept = append(kept, c)
+			if solver.value(literals[0]) < 0 {
+				kept = append(kept, watchers[index+1:]...)
+				solver.watches[literalIndex(falseLiteral)] = kept
+				solver.queueHead = len(solver.trail)
+				return c
+			}
+			solver.enqueue(literals[0], c)
+		}
+		solver.watches[literalIndex(falseLiteral)] = kept
+	}
+	return nil
+}
+
+/*
+Learn a clause from a conflict: the literals of the conflict are replaced by the
+literals implying them until a single literal of the current level remains, the
+first unique implication point. Return the clause, with its asserting literal first,
+and the level to backjump to
+*/
+func (solver *SATSolver) analyze(conflict *satClause) ([]Literal, int) {
+	learnt := []Literal{0}
+	pathCount := 0
+	var implied Literal
+	index := len(solver.trail) - 1
+
+	for c := conflict; ; {
+		if c.learnt {
+			solver.bumpClause(c)
+		}
+
+		// the first literal of a reason is the literal it implied
+		start := 0
+		if implied != 0 {
+			start = 1
+		}
+
+		for _, literal := range c.literals[start:] {
+			variable := literal.Variable()
+			if solver.seen[variable] || solver.levels[variable] == 0 {
+				continue
+			}
+
+			solver.bumpVariable(variable)
+			solver.seen[variable] = true
+			if solver.levels[variable] >= solver.decisionLevel() {
+				pathCount++
+			} else {
+				learnt = append(learnt, literal)
+			}
+		}
+
+		for !solver.seen[solver.trail[index].Variable()] {
+			index--
+		}
+		implied = solver.trail[index]
+		index--
+		c = solver.reasons[implied.Variable()]
+		solver.seen[implied.Variable()] = false
+
+		pathCount--
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = implied.Not()
+
+	// remove the literals implied by the other literals of the clause
+	minimized := []Literal{learnt[0]}
+	for _, literal := range learnt[1:] {
+		if !solver.isRedundant(literal) {
+			minimized = append(minimized, literal)
+		}
+	}
+	for _, literal := range learnt {
+		solver.seen[literal.Variable()] = false
+	}
+	learnt = minimized
+
+	level := 0
+	for position := 1; position < len(learnt); position++ {
+		if solver.levels[learnt[position].Variable()] > level {
+			level = solver.levels[learnt[position].Variable()]
+			learnt[1], learnt[position] = learnt[position], learnt[1]
+		}
+	}
+	return learnt, level
+}
+
+/*
+Return true if the reason of the literal only contains literals of the learnt clause
+*/
+func (solver *SATSolver) isRedundant(literal Literal) bool {
+	reason := solver.reasons[literal.Variable()]
+	if reason == nil {
+		return false
+	}
+
+	for _, other := range reason.literals[1:] {
+		if !solver.seen[other.Variable()] && solver.levels[other.Variable()] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Return the assumptions implying that the given assumption is false
+*/
+func (solver *SATSolver) analyzeFinal(assumption Literal) []Literal {
+	conflict := []Literal{assumption}
+	if solver.decisionLevel() == 0 {
+		return conflict
+	}
+
+	solver.seen[assumption.Variable()] = true
+	for index := len(solver.trail) - 1; index >= solver.trailLimits[0]; index-- {
+		variable := solver.trail[index].Variable()
+		if !solver.seen[variable] {
+			continue
+		}
+
+		if reason := solver.reasons[variable]; reason == nil {
+			// the decisions below the assumption levels are assumptions
+			conflict = append(conflict, solver.trail[index])
+		} else {
+			for _, literal := range reason.literals[1:] {
+				if solver.levels[literal.Variable()] > 0 {
+					solver.seen[literal.Variable()] = true
+				}
+			}
+		}
+		solver.seen[variable] = false
+	}
+	solver.seen[assumption.Variable()] = false
+	return conflict
+}
+
+/*
+Remove half of the learnt clauses, the least active first, keeping the binary
+clauses and the reasons of the current assignment
+*/
+func (solver *SATSolver) reduceLearnts() {
+	sort.SliceStable(solver.learnts, func(i, j int) bool {
+		return solver.learnts[i].activity < solver.learnts[j].activity
+	})
+
+	kept := []*satClause{}
+	for index, c := range solver.learnts {
+		locked := solver.reasons[c.literals[0].Variable()] == c && solver.value(c.literals[0]) > 0
+		if index < len(solver.learnts)/2 && len(c.literals) > 2 && !locked {
+			solver.detach(c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	solver.learnts = kept
+	solver.maxLearnts *= 1.1
+}
+
+func (solver *SATSolver) attach(c *satClause) {
+	for _, literal := range c.literals[:2] {
+		solver.watches[literalIndex(literal)] = append(solver.watches[literalIndex(literal)], c)
+	}
+}
+
+func (solver *SATSolver) detach(c *satClause) {
+	for _, literal := range c.literals[:2] {
+		watchers := solver.watches[literalIndex(literal)]
+		for index, watcher := range watchers {
+			if watcher == c {
+				watchers[index] = watchers[len(watchers)-1]
+				solver.watches[literalIndex(literal)] = watchers[:len(watchers)-1]
+				break
+			}
+		}
+	}
+}
+
+func (solver *SATSolver) pickBranchVariable() int {
+	for !solver.order.isEmpty() {
+		variable := solver.order.removeMax()
+		if solver.assigns[variable] == 0 {
+			return variable
+		}
+	}
+	return 0
+}
+
+func (solver *SATSolver) enqueue(literal Literal, reason *satClause) {
+	variable := literal.Variable()
+	solver.assigns[variable] = 1
+	if literal.IsNegated() {
+		solver.assigns[variable] = -1
+	}
+	solver.levels[variable] = solver.decisionLevel()
+	solver.reasons[variable] = reason
+	solver.trail = append(solver.trail, literal)
+}
+
+func (solver *SATSolver) cancelUntil(level int) {
+	if solver.decisionLevel() <= level {
+		return
+	}
+
+	for index := len(solver.trail) - 1; index >= solver.trailLimits[level]; index-- {
+		variable := solver.trail[index].Variable()
+		solver.polarity[variable] = !solver.trail[index].IsNegated()
+		solver.assigns[variable] = 0
+		solver.reasons[variable] = nil
+		if !solver.order.contains(variable) {
+			solver.order.insert(variable)
+		}
+	}
+
+	solver.trail = solver.trail[:solver.trailLimits[level]]
+	solver.trailLimits = solver.trailLimits[:level]
+	solver.queueHead = len(solver.trail)
+}
+
+func (solver *SATSolver) decisionLevel() int {
+	return len(solver.trailLimits)
+}
+
+/*
+Return 1 if the literal is true, -1 if it is false and 0 if it is unassigned
+*/
+func (solver *SATSolver) value(literal Literal) int8 {
+	if literal.IsNegated() {
+		return -solver.assigns[literal.Variable()]
+	}
+	return solver.assigns[literal.Variable()]
+}
+
+func (solver *SATSolver) bumpVariable(variable int) {
+	solver.activity[variable] += solver.activityIncrement
+	if solver.activity[variable] > 1e100 {
+		for index := range solver.activity {
+			solver.activity[index] *= 1e-100
+		}
+		solver.activityIncrement *= 1e-100
+	}
+
+	if solver.order.contains(variable) {
+		solver.order.increase(variable)
+	}
+}
+
+func (solver *SATSolver) bumpClause(c *satClause) {
+	c.activity += solver.clauseIncrement
+	if c.activity > 1e20 {
+		for _, learnt := range solver.learnts {
+			learnt.activity *= 1e-20
+		}
+		solver.clauseIncrement *= 1e-20
+	}
+}
+
+/*
+Return the index of a literal in the watch lists: 2k for the variable k, 2k+1 for its negation
+*/
+func literalIndex(literal Literal) int {
+	if literal.IsNegated() {
+		return 2*literal.Variable() + 1
+	}
+	return 2 * literal.Variable()
+}
+
+/*
+Return the element of the Luby sequence 1, 1, 2, 1, 1, 2, 4, 1, 1, 2, ...
+*/
+func lubySequence(index int) int {
+	size, power := 1, 1
+	for size < index+1 {
+		size = 2*size + 1
+		power *= 2
+	}
+
+	for size-1 != index {
+		size = (size - 1) / 2
+		power /= 2
+		index %= size
+	}
+	return power
+}
+
+/*
+Binary max heap of variables ordered by activity
+*/
+type variableHeap struct {
+	heap      []int
+	positions []int // Position of each variable in the heap, -1 when absent
+	activity  *[]float64
+}
+
+func (order *variableHeap) isEmpty() bool {
+	return len(order.heap) == 0
+}
+
+func (order *variableHeap) contains(variable int) bool {
+	return order.positions[variable] >= 0
+}
+
+func (order *variableHeap) insert(variable int) {
+	order.positions[variable] = len(order.heap)
+	order.heap = append(order.heap, variable)
+	order.up(len(order.heap) - 1)
+}
+
+func (order *variableHeap) increase(variable int) {
+	order.up(order.positions[variable])
+}
+
+func (order *variableHeap) removeMax() int {
+	variable := order.heap[0]
+	last := order.heap[len(order.heap)-1]
+	order.heap = order.heap[:len(order.heap)-1]
+	order.positions[variable] = -1
+
+	if len(order.heap) > 0 {
+		order.heap[0] = last
+		order.positions[last] = 0
+		order.down(0)
+	}
+	return variable
+}
+
+func (order *variableHeap) less(first, second int) bool {
+	return (*order.activity)[order.heap[first]] < (*order.activity)[order.heap[second]]
+}
+
+func (order *variableHeap) swap(first, second int) {
+	order.heap[first], order.heap[second] = order.heap[second], order.heap[first]
+	order.positions[order.heap[first]] = first
+	order.positions[order.heap[second]] = second
+}
+
+func (order *variableHeap) up(position int) {
+	for position > 0 {
+		parent := (position - 1) / 2
+		if !order.less(parent, position) {
+			return
+		}
+		order.swap(parent, position)
+		position = parent
+	}
+}
+
+func (order *variableHeap) down(position int) {
+	for {
+		largest := position
+		for _, child := range []int{2*position + 1, 2*position + 2} {
+			if child < len(order.heap) && order.less(largest, child) {
+				largest = child
+			}
+		}
+
+		if largest == position {
+			return
+		}
+		order.swap(position, largest)
+		position = largest
+	}
+}
+
+/*
+Search an assignment of the variables satisfying the expression with the CDCL solver.
+Return false when the expression is unsatisfiable
+*/
+func Solve(expr Expression) (map[string]bool, bool) {
+	return PlaistedGreenbaumCNF(expr).Solve()
+}
+
+/*
+Search an assignment of the variables of the expression satisfying the CNF. The
+auxiliary variables are not part of the assignment
+*/
+func (cnf *CNF) Solve() (map[string]bool, bool) {
+	solver := NewSATSolverFromCNF(cnf)
+	if !solver.Solve() {
+		return nil, false
+	}
+	return cnf.modelOf(solver), true
+}
+
+/*
+Return the values of the variables of the expression in the last model of the solver
+*/
+func (cnf *CNF) modelOf(solver *SATSolver) map[string]bool {
+	model := make(map[string]bool, cnf.NbrInputs)
+	for index, variable := range cnf.Variables[:cnf.NbrInputs] {
+		model[variable] = solver.Value(Literal(index + 1))
+	}
+	return model
+}
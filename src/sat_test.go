@@ -0,0 +1,229 @@
+package logic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+Return a random 3-SAT CNF. When planted is not nil, every clause is satisfied by it
+*/
+func randomThreeSAT(random *rand.Rand, nbrVariables, nbrClauses int, planted []bool) *CNF {
+	variables := []string{}
+	for index := 0; index < nbrVariables; index++ {
+		variables = append(variables, fmt.Sprintf("x%d", index))
+	}
+
+	cnf := NewCNF(variables)
+	for len(cnf.Clauses) < nbrClauses {
+		clause := Clause{}
+		satisfied := false
+		for len(clause) < 3 {
+			variable := random.Intn(nbrVariables)
+			literal := Literal(variable + 1)
+			if random.Intn(2) == 0 {
+				literal = literal.Not()
+			}
+			clause = append(clause, literal)
+			satisfied = satisfied || planted == nil || planted[variable] != literal.IsNegated()
+		}
+
+		if satisfied {
+			cnf.AddClause(clause...)
+		}
+	}
+	return cnf
+}
+
+/*
+Return true if some assignment of the variables satisfies the CNF
+*/
+func bruteForceSatisfiable(cnf *CNF) bool {
+	for row := 0; row < 1<<len(cnf.Variables); row++ {
+		if cnf.Eval(assignmentOf(row, cnf.Variables)) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Return the CNF saying that nbrPigeons pigeons are in nbrHoles holes, at most one per hole
+*/
+func pigeonholeCNF(nbrPigeons, nbrHoles int) *CNF {
+	cnf := NewCNF(nil)
+	for pigeon := 0; pigeon < nbrPigeons; pigeon++ {
+		clause := Clause{}
+		for hole := 0; hole < nbrHoles; hole++ {
+			clause = append(clause, cnf.Literal(fmt.Sprintf("p%d_%d", pigeon, hole)))
+		}
+		cnf.AddClause(clause...)
+	}
+
+	for hole := 0; hole < nbrHoles; hole++ {
+		for first := 0; first < nbrPigeons; first++ {
+			for second := first + 1; second < nbrPigeons; second++ {
+				cnf.AddClause(cnf.Literal(fmt.Sprintf("p%d_%d", first, hole)).Not(), cnf.Literal(fmt.Sprintf("p%d_%d", second, hole)).Not())
+			}
+		}
+	}
+	cnf.NbrInputs = len(cnf.Variables)
+	return cnf
+}
+
+func TestSolve(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name        string
+		input       string
+		satisfiable bool
+	}{
+		{"test variable", "a", true},
+		{"test contradiction", "a & !a", false},
+		{"test tautology", "a | !a", true},
+		{"test constant true", "1", true},
+		{"test constant false", "0", false},
+		{"test implies", "(a -> b) & a & !b", false},
+		{"test xor chain", "(a + b) & (b + c) & (a + c)", false},
+		{"test equivalence", "(a <-> b) & (b <-> !c) & a & c", false},
+		{"test satisfiable", "(a | b) & (!a | c) & (!b | !c)", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseInput(test.input)
+			assert.Nil(err)
+
+			model, ok := Solve(expr)
+			assert.Equal(test.satisfiable, ok, test.name)
+			if ok {
+				assert.True(expr.Eval(model), test.name)
+				assert.Len(model, len(Variables(expr)))
+			}
+		})
+	}
+}
+
+func TestSolveRandomExpressions(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(12))
+	variables := []string{"a", "b", "c", "d", "e"}
+
+	for i := 0; i < 300; i++ {
+		expr := randomExpression(random, 5, variables)
+		model, ok := Solve(expr)
+		assert.Equal(len(mintermsOf(expr, Variables(expr))) > 0, ok, Print(expr, ASCII))
+		if ok {
+			assert.True(expr.Eval(model), Print(expr, ASCII))
+		}
+	}
+}
+
+func TestSolveRandomThreeSAT(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(13))
+
+	// around the ratio of 4.26 clauses per variable, half of the problems are satisfiable
+	for i := 0; i < 50; i++ {
+		cnf := randomThreeSAT(random, 14, 60, nil)
+		model, ok := cnf.Solve()
+		assert.Equal(bruteForceSatisfiable(cnf), ok)
+		if ok {
+			assert.True(cnf.Eval(model))
+		}
+	}
+}
+
+func TestSolveManyVariables(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewSource(14))
+
+	planted := make([]bool, 300)
+	for index := range planted {
+		planted[index] = random.Intn(2) == 0
+	}
+
+	cnf := randomThreeSAT(random, 300, 1200, planted)
+	model, ok := cnf.Solve()
+	assert.True(ok)
+	assert.True(cnf.Eval(model))
+}
+
+func TestSolvePigeonhole(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := pigeonholeCNF(7, 6).Solve()
+	assert.False(ok)
+
+	model, ok := pigeonholeCNF(6, 6).Solve()
+	assert.True(ok)
+	assert.True(pigeonholeCNF(6, 6).Eval(model))
+}
+
+func TestSATSolverAssumptions(t *testing.T) {
+	assert := assert.New(t)
+
+	// a -> b, b -> c
+	solver := NewSATSolver(4)
+	solver.AddClause(-1, 2)
+	solver.AddClause(-2, 3)
+
+	assert.True(solver.Solve(1))
+	assert.True(solver.Value(3))
+
+	assert.False(solver.Solve(4, 1, -3))
+	assert.ElementsMatch([]Literal{1, -3}, solver.FailedAssumptions())
+
+	// the clauses are still satisfiable without the assumptions
+	assert.True(solver.Solve())
+	assert.True(solver.Solve(-3))
+	assert.False(solver.Value(1))
+
+	// incremental: the clauses can be added after a search
+	assert.True(solver.AddClause(1))
+	assert.False(solver.Solve(-3))
+	assert.ElementsMatch([]Literal{-3}, solver.FailedAssumptions())
+
+	assert.False(solver.AddClause(-3))
+	assert.False(solver.Solve())
+	assert.Empty(solver.FailedAssumptions())
+}
+
+func TestSATSolverNewVariable(t *testing.T) {
+	assert := assert.New(t)
+
+	solver := NewSATSolver(0)
+	a, b := solver.NewVariable(), solver.NewVariable()
+	assert.Equal(Literal(1), a)
+	assert.Equal(Literal(2), b)
+	assert.Equal(2, solver.NbrVariables())
+
+	solver.AddClause(a.Not(), b.Not())
+	solver.AddClause(a, b)
+	assert.True(solver.Solve(a))
+	assert.False(solver.Value(b))
+	assert.Greater(solver.Statistics().Propagations, 0)
+}
+
+func TestLubySequence(t *testing.T) {
+	assert := assert.New(t)
+
+	sequence := []int{}
+	for index := 0; index < 15; index++ {
+		sequence = append(sequence, lubySequence(index))
+	}
+	assert.Equal([]int{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8}, sequence)
+}
+
+func BenchmarkSolve(b *testing.B) {
+	random := rand.New(rand.NewSource(15))
+	cnf := randomThreeSAT(random, 120, 510, nil)
+
+	for i := 0; i < b.N; i++ {
+		cnf.Solve()
+	}
+}
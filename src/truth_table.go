@@ -0,0 +1,265 @@
+package logic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	boolutil "github.com/dterbah/go-logic/src/utils"
+	"github.com/olekukonko/tablewriter"
+)
+
+/*
+Output format of a truth table
+*/
+type TruthTableFormat int
+
+const (
+	TruthTableText     TruthTableFormat = iota // Table of the terminal
+	TruthTableCSV                              // Comma-separated values, with a header line
+	TruthTableTSV                              // Tab-separated values, with a header line
+	TruthTableJSON                             // JSON array of rows, each row being an object
+	TruthTableJSONL                            // JSON Lines, an object per row
+	TruthTableMarkdown                         // Markdown table
+	TruthTableLaTeX                            // LaTeX tabular environment
+	TruthTableHTML                             // HTML table
+)
+
+var truthTableFormatNames = map[TruthTableFormat]string{
+	TruthTableText:     "text",
+	TruthTableCSV:      "csv",
+	TruthTableTSV:      "tsv",
+	TruthTableJSON:     "json",
+	TruthTableJSONL:    "jsonl",
+	TruthTableMarkdown: "markdown",
+	TruthTableLaTeX:    "latex",
+	TruthTableHTML:     "html",
+}
+
+func (format TruthTableFormat) String() string {
+	return truthTableFormatNames[format]
+}
+
+/*
+Return the format of truth table associated to a name (text, csv, tsv, json, jsonl,
+markdown, latex or html)
+*/
+func ParseTruthTableFormat(name string) (TruthTableFormat, error) {
+	for format, formatName := range truthTableFormatNames {
+		if strings.EqualFold(name, formatName) {
+			return format, nil
+		}
+	}
+
+	return TruthTableText, fmt.Errorf("unknown truth table format %s, expected text, csv, tsv, json, jsonl, markdown, latex or html", name)
+}
+
+/*
+Expression shown as a column of a truth table. The column shows DONT_CARE_VALUE on the
+rows for which DontCares is true, DontCares being ignored when nil
+*/
+type TruthTableColumn struct {
+	Name      string
+	Expr      Expression
+	DontCares Expression
+}
+
+/*
+Truth table of expressions, independent of its rendering
+*/
+type TruthTable struct {
+	Variables []string   // Variables of the table, the variable k being the bit k of the row numbers
+	Columns   []string   // Names of the columns of the expressions, after the variables
+	Rows      [][]string // Values of the variables then of the expressions: 0, 1 or DONT_CARE_VALUE
+}
+
+/*
+Create the truth table of the columns over the variables. The row k is the assignment
+where the variable at index k is true when the bit k of the row is set, as by Minterms
+*/
+func NewTruthTable(variables []string, columns ...TruthTableColumn) *TruthTable {
+	table := &TruthTable{Variables: variables, Columns: []string{}, Rows: [][]string{}}
+	for _, column := range columns {
+		table.Columns = append(table.Columns, column.Name)
+	}
+
+	for row := 0; row < 1<<len(variables); row++ {
+		assignment := assignmentOf(row, variables)
+		values := []string{}
+		for _, variable := range variables {
+			values = append(values, boolutil.BoolToString(assignment[variable]))
+		}
+
+		for _, column := range columns {
+			if column.DontCares != nil && column.DontCares.Eval(assignment) {
+				values = append(values, DONT_CARE_VALUE)
+			} else {
+				values = append(values, boolutil.BoolToString(column.Expr.Eval(assignment)))
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table
+}
+
+/*
+Return the names of the variables then of the columns
+*/
+func (table *TruthTable) Headers() []string {
+	return append(append([]string{}, table.Variables...), table.Columns...)
+}
+
+/*
+Return the values of the column of an expression, from the first row
+*/
+func (table *TruthTable) Column(index int) []string {
+	values := []string{}
+	for _, row := range table.Rows {
+		values = append(values, row[len(table.Variables)+index])
+	}
+	return values
+}
+
+/*
+Write the table in a format
+*/
+func (table *TruthTable) Write(writer io.Writer, format TruthTableFormat) error {
+	switch format {
+	case TruthTableText:
+		return table.writeText(writer)
+	case TruthTableCSV:
+		return table.writeSeparatedValues(writer, ',')
+	case TruthTableTSV:
+		return table.writeSeparatedValues(writer, '\t')
+	case TruthTableJSON:
+		return table.writeJSON(writer, false)
+	case TruthTableJSONL:
+		return table.writeJSON(writer, true)
+	case TruthTableMarkdown:
+		return table.writeMarkdown(writer)
+	case TruthTableLaTeX:
+		return table.writeLaTeX(writer)
+	case TruthTableHTML:
+		return table.writeHTML(writer)
+	}
+	return fmt.Errorf("unknown truth table format %d", format)
+}
+
+func (table *TruthTable) writeText(writer io.Writer) error {
+	output := tablewriter.NewWriter(writer)
+	// keep variable names such as reset_n untouched
+	output.SetAutoFormatHeaders(false)
+	output.SetHeader(table.Headers())
+	output.AppendBulk(table.Rows)
+	output.Render()
+	return nil
+}
+
+func (table *TruthTable) writeSeparatedValues(writer io.Writer, separator rune) error {
+	output := csv.NewWriter(writer)
+	output.Comma = separator
+	if err := output.Write(table.Headers()); err != nil {
+		return err
+	}
+	if err := output.WriteAll(table.Rows); err != nil {
+		return err
+	}
+	return output.Error()
+}
+
+/*
+Write the rows as objects whose keys are the headers, in their order. The values are
+booleans, and null for the don't care rows
+*/
+func (table *TruthTable) writeJSON(writer io.Writer, lines bool) error {
+	headers := table.Headers()
+	objects := []string{}
+	for _, row := range table.Rows {
+		fields := []string{}
+		for index, value := range row {
+			key, err := json.Marshal(headers[index])
+			if err != nil {
+				return err
+			}
+
+			jsonValue := map[string]string{"0": "false", "1": "true"}[value]
+			if jsonValue == "" {
+				jsonValue = "null"
+			}
+			fields = append(fields, fmt.Sprintf("%s:%s", key, jsonValue))
+		}
+		objects = append(objects, "{"+strings.Join(fields, ",")+"}")
+	}
+
+	var output string
+	if lines {
+		output = strings.Join(objects, "\n") + "\n"
+	} else {
+		output = "[\n  " + strings.Join(objects, ",\n  ") + "\n]\n"
+	}
+	_, err := io.WriteString(writer, output)
+	return err
+}
+
+func (table *TruthTable) writeMarkdown(writer io.Writer) error {
+	escape := strings.NewReplacer("|", "\\|")
+	lines := []string{}
+	for _, row := range append([][]string{table.Headers()}, table.Rows...) {
+		cells := []string{}
+		for _, cell := range row {
+			cells = append(cells, escape.Replace(cell))
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+
+	separator := "|" + strings.Repeat(":-:|", len(table.Variables)+len(table.Columns))
+	lines = append(lines[:1], append([]string{separator}, lines[1:]...)...)
+	_, err := io.WriteString(writer, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func (table *TruthTable) writeLaTeX(writer io.Writer) error {
+	escape := strings.NewReplacer(
+		`\`, `\textbackslash{}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`,
+		"{", `\{`, "}", `\}`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+		"|", `\textbar{}`, "<", `\textless{}`, ">", `\textgreater{}`,
+	)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("\\begin{tabular}{%s|%s}\n", strings.Repeat("c", len(table.Variables)), strings.Repeat("c", len(table.Columns))))
+	headers := []string{}
+	for _, header := range table.Headers() {
+		headers = append(headers, escape.Replace(header))
+	}
+	builder.WriteString("  " + strings.Join(headers, " & ") + " \\\\\n  \\hline\n")
+	for _, row := range table.Rows {
+		builder.WriteString("  " + strings.Join(row, " & ") + " \\\\\n")
+	}
+	builder.WriteString("\\end{tabular}\n")
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func (table *TruthTable) writeHTML(writer io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, header := range table.Headers() {
+		builder.WriteString("<th>" + html.EscapeString(header) + "</th>")
+	}
+	builder.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+	for _, row := range table.Rows {
+		builder.WriteString("    <tr>")
+		for _, value := range row {
+			builder.WriteString("<td>" + html.EscapeString(value) + "</td>")
+		}
+		builder.WriteString("</tr>\n")
+	}
+	builder.WriteString("  </tbody>\n</table>\n")
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTruthTableFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	for format := range truthTableFormatNames {
+		parsed, err := ParseTruthTableFormat(format.String())
+		assert.Nil(err)
+		assert.Equal(format, parsed)
+	}
+
+	_, err := ParseTruthTableFormat("xml")
+	assert.Equal("unknown truth table format xml, expected text, csv, tsv, json, jsonl, markdown, latex or html", err.Error())
+}
+
+func TestNewTruthTable(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a | b")
+	dontCares, _ := parseInput("a & b")
+	table := NewTruthTable([]string{"a", "b"},
+		TruthTableColumn{Name: "a | b", Expr: expr, DontCares: dontCares},
+		TruthTableColumn{Name: "a + b", Expr: NewXORExpression(NewVarExpression("a"), NewVarExpression("b"))},
+	)
+
+	assert.Equal([]string{"a", "b", "a | b", "a + b"}, table.Headers())
+	assert.Equal([][]string{
+		{"0", "0", "0", "0"},
+		{"1", "0", "1", "1"},
+		{"0", "1", "1", "1"},
+		{"1", "1", "X", "0"},
+	}, table.Rows)
+	assert.Equal([]string{"0", "1", "1", "X"}, table.Column(0))
+	assert.Equal([]string{"0", "1", "1", "0"}, table.Column(1))
+}
+
+func TestTruthTableWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	expr, _ := parseInput("a | b_1")
+	dontCares, _ := parseInput("a & b_1")
+	table := NewTruthTable([]string{"a", "b_1"}, TruthTableColumn{Name: "a | b_1", Expr: expr, DontCares: dontCares})
+
+	tests := []struct {
+		name     string
+		format   TruthTableFormat
+		expected []string
+	}{
+		{"test csv", TruthTableCSV, []string{"a,b_1,a | b_1", "0,0,0", "1,0,1", "0,1,1", "1,1,X"}},
+		{"test tsv", TruthTableTSV, []string{"a\tb_1\ta | b_1", "0\t0\t0", "1\t0\t1", "0\t1\t1", "1\t1\tX"}},
+		{"test json", TruthTableJSON, []string{
+			"[",
+			`  {"a":false,"b_1":false,"a | b_1":false},`,
+			`  {"a":true,"b_1":false,"a | b_1":true},`,
+			`  {"a":false,"b_1":true,"a | b_1":true},`,
+			`  {"a":true,"b_1":true,"a | b_1":null}`,
+			"]",
+		}},
+		{"test json lines", TruthTableJSONL, []string{
+			`{"a":false,"b_1":false,"a | b_1":false}`,
+			`{"a":true,"b_1":false,"a | b_1":true}`,
+			`{"a":false,"b_1":true,"a | b_1":true}`,
+			`{"a":true,"b_1":true,"a | b_1":null}`,
+		}},
+		{"test markdown", TruthTableMarkdown, []string{"| a | b_1 | a \\| b_1 |", "|:-:|:-:|:-:|", "| 0 | 0 | 0 |", "| 1 | 0 | 1 |", "| 0 | 1 | 1 |", "| 1 | 1 | X |"}},
+		{"test latex", TruthTableLaTeX, []string{
+			`\begin{tabular}{cc|c}`,
+			`  a & b\_1 & a \textbar{} b\_1 \\`,
+			`  \hline`,
+			`  0 & 0 & 0 \\`,
+			`  1 & 0 & 1 \\`,
+			`  0 & 1 & 1 \\`,
+			`  1 & 1 & X \\`,
+			`\end{tabular}`,
+		}},
+		{"test html", TruthTableHTML, []string{
+			"<table>",
+			"  <thead>",
+			"    <tr><th>a</th><th>b_1</th><th>a | b_1</th></tr>",
+			"  </thead>",
+			"  <tbody>",
+			"    <tr><td>0</td><td>0</td><td>0</td></tr>",
+			"    <tr><td>1</td><td>0</td><td>1</td></tr>",
+			"    <tr><td>0</td><td>1</td><td>1</td></tr>",
+			"    <tr><td>1</td><td>1</td><td>X</td></tr>",
+			"  </tbody>",
+			"</table>",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			assert.Nil(table.Write(&buffer, test.format))
+			assert.Equal(strings.Join(test.expected, "\n")+"\n", buffer.String(), test.name)
+		})
+	}
+
+	// the JSON output is valid, and the text output is the table of the terminal
+	var buffer bytes.Buffer
+	assert.Nil(table.Write(&buffer, TruthTableJSON))
+	rows := []map[string]any{}
+	assert.Nil(json.Unmarshal(buffer.Bytes(), &rows))
+	assert.Equal(map[string]any{"a": true, "b_1": true, "a | b_1": nil}, rows[3])
+
+	buffer.Reset()
+	assert.Nil(table.Write(&buffer, TruthTableText))
+	assert.Contains(buffer.String(), "| a | b_1 | a | b_1 |")
+}
+
+func TestTruthTableEscaping(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewTruthTable([]string{"a"}, TruthTableColumn{Name: `a, "b" <c>`, Expr: NewVarExpression("a")})
+
+	var buffer bytes.Buffer
+	assert.Nil(table.Write(&buffer, TruthTableCSV))
+	assert.Equal("a,\"a, \"\"b\"\" <c>\"\n0,0\n1,1\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(table.Write(&buffer, TruthTableHTML))
+	assert.Contains(buffer.String(), "<th>a, &#34;b&#34; &lt;c&gt;</th>")
+}
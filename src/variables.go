@@ -0,0 +1,91 @@
+package logic
+
+/*
+Return the variables of an expression, in their order of appearance
+*/
+func Variables(expr Expression) []string {
+	variables := []string{}
+	seen := make(map[string]bool)
+	collectVariables(expr, seen, &variables)
+	return variables
+}
+
+/*
+Return the variables of several expressions, in their order of appearance
+*/
+func variablesOf(exprs ...Expression) []string {
+	variables := []string{}
+	seen := make(map[string]bool)
+	for _, expr := range exprs {
+		collectVariables(expr, seen, &variables)
+	}
+	return variables
+}
+
+func collectVariables(expr Expression, seen map[string]bool, variables *[]string) {
+	if value, ok := expr.(*VarExpression); ok {
+		if !seen[value.variable] {
+			seen[value.variable] = true
+			*variables = append(*variables, value.variable)
+		}
+		return
+	}
+
+	for _, operand := range operands(expr) {
+		collectVariables(operand, seen, variables)
+	}
+}
+
+/*
+Return the direct operands of an expression
+*/
+func operands(expr Expression) []Expression {
+	switch value := expr.(type) {
+	case *NotExpression:
+		return []Expression{value.expr}
+	case *AndExpression:
+		return []Expression{value.left, value.right}
+	case *OrExpression:
+		return []Expression{value.left, value.right}
+	case *XORExpression:
+		return []Expression{value.left, value.right}
+	case *ImpliesExpression:
+		return []Expression{value.left, value.right}
+	case *EquivalenceExpression:
+		return []Expression{value.left, value.right}
+	case *CardinalityExpression:
+		return value.operands
+	case *PseudoBooleanExpression:
+		return value.operands
+	default:
+		return nil
+	}
+}
+
+/*
+Return the assignment of a row of the truth table: the variable at index k is
+true when the bit k of the row is set. The truth table of the Runner and the
+minimizers enumerate their rows with this function, so that a row number means
+the same assignment everywhere
+*/
+func assignmentOf(row int, variables []string) map[string]bool {
+	assignment := make(map[string]bool, len(variables))
+	for index, variable := range variables {
+		assignment[variable] = row&(1<<index) != 0
+	}
+	return assignment
+}
+
+/*
+Return the rows of the truth table for which the expression is true, numbered
+as by assignmentOf
+*/
+func mintermsOf(expr Expression, variables []string) []int {
+	minterms := []int{}
+	for row := 0; row < 1<<len(variables); row++ {
+		if expr.Eval(assignmentOf(row, variables)) {
+			minterms = append(minterms, row)
+		}
+	}
+	return minterms
+}